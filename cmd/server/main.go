@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"net"
 	"net/http"
@@ -10,18 +11,29 @@ import (
 	"syscall"
 	"time"
 
+	_ "github.com/lib/pq"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"go.temporal.io/sdk/client"
 	"go.temporal.io/sdk/worker"
+	"go.temporal.io/sdk/workflow"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
 
 	orderv1 "github.com/cypherlabdev/cypherlabdev-protos/gen/go/order/v1"
+	walletpb "github.com/cypherlabdev/cypherlabdev-protos/gen/go/wallet/v1"
 	"github.com/cypherlabdev/order-validator-service/internal/activity"
 	"github.com/cypherlabdev/order-validator-service/internal/config"
+	"github.com/cypherlabdev/order-validator-service/internal/events"
 	grpcHandler "github.com/cypherlabdev/order-validator-service/internal/handler/grpc"
-	"github.com/cypherlabdev/order-validator-service/internal/workflow"
+	httpHandler "github.com/cypherlabdev/order-validator-service/internal/handler/http"
+	"github.com/cypherlabdev/order-validator-service/internal/ledger"
+	"github.com/cypherlabdev/order-validator-service/internal/recovery"
+	"github.com/cypherlabdev/order-validator-service/internal/tracing"
+	"github.com/cypherlabdev/order-validator-service/internal/transport/websocket"
+	validatorworkflow "github.com/cypherlabdev/order-validator-service/internal/workflow"
 )
 
 func main() {
@@ -35,11 +47,30 @@ func main() {
 	logger := initLogger(cfg.Logger)
 	logger.Info().Msg("order-validator-service starting")
 
+	// Initialize tracing. Shutdown is deferred unconditionally; it's a no-op
+	// when tracing is disabled.
+	shutdownTracing, err := tracing.Init(context.Background(), cfg.Tracing)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("failed to initialize tracing")
+	}
+	defer func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+		defer cancel()
+		if err := shutdownTracing(shutdownCtx); err != nil {
+			logger.Warn().Err(err).Msg("failed to shut down tracing cleanly")
+		}
+	}()
+
+	// contextPropagator carries the active span context from the gRPC
+	// handler, through the workflow, to every activity it schedules
+	contextPropagator := tracing.NewContextPropagator()
+
 	// Initialize Temporal client
 	temporalClient, err := client.Dial(client.Options{
-		HostPort:  cfg.Temporal.ServerAddress,
-		Namespace: cfg.Temporal.Namespace,
-		Logger:    newTemporalLogger(logger),
+		HostPort:           cfg.Temporal.ServerAddress,
+		Namespace:          cfg.Temporal.Namespace,
+		Logger:             newTemporalLogger(logger),
+		ContextPropagators: []workflow.ContextPropagator{contextPropagator},
 	})
 	if err != nil {
 		logger.Fatal().Err(err).Msg("failed to create Temporal client")
@@ -51,34 +82,110 @@ func main() {
 		Str("namespace", cfg.Temporal.Namespace).
 		Msg("connected to Temporal server")
 
+	// eventPublisher carries saga/order lifecycle events from activities to
+	// any number of subscribers, including the WebSocket bridge below
+	eventPublisher := events.NewRedisPublisher(cfg.Services.RedisAddr, logger)
+
 	// Initialize activities
-	walletActivities, err := activity.NewWalletActivities(cfg.Services.WalletServiceAddr, logger)
+	walletActivities, err := activity.NewWalletActivities(cfg.Services.WalletServiceAddr, eventPublisher, logger)
 	if err != nil {
 		logger.Fatal().Err(err).Msg("failed to initialize wallet activities")
 	}
 
-	validationActivities := activity.NewValidationActivities(logger)
+	rulesProvider, err := activity.NewFileRulesProvider(cfg.Validation.RulesFilePath, activity.DefaultValidationRulesConfig())
+	if err != nil {
+		logger.Fatal().Err(err).Msg("failed to initialize validation rules provider")
+	}
+
+	validationActivities := activity.NewValidationActivities(rulesProvider, eventPublisher, logger, cfg.Validation.MaxOddsDeviationPct)
 
-	orderBookActivities, err := activity.NewOrderBookActivities(cfg.Services.OrderBookServiceAddr, logger)
+	orderBookActivities, err := activity.NewOrderBookActivities(cfg.Services.OrderBookServiceAddr, eventPublisher, logger)
 	if err != nil {
 		logger.Fatal().Err(err).Msg("failed to initialize order-book activities")
 	}
 
+	marketStateActivities, err := activity.NewMarketStateActivities(cfg.Services.MarketDataServiceAddr, logger)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("failed to initialize market state activities")
+	}
+
+	positionActivities, err := activity.NewPositionActivities(cfg.Services.WalletServiceAddr, cfg.Services.OrderBookServiceAddr, cfg.Risk, logger)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("failed to initialize position activities")
+	}
+
+	idempotencyDB, err := sql.Open("postgres", cfg.Idempotency.PostgresDSN)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("failed to open idempotency database connection")
+	}
+	defer idempotencyDB.Close()
+
+	idempotencyStore := activity.NewPostgresIdempotencyStore(idempotencyDB)
+	idempotencyActivities := activity.NewIdempotencyActivities(idempotencyStore, cfg.Idempotency.TTL, logger)
+
+	// Fills share the idempotency database; a Redis cache sits in front of
+	// the hot GetFillsByOrder read path.
+	fillRepository := activity.NewCachedFillRepository(
+		activity.NewPostgresFillRepository(idempotencyDB),
+		cfg.Services.RedisAddr,
+	)
+	fillActivities := activity.NewFillActivities(fillRepository, eventPublisher, logger)
+
+	// Ledger shares the idempotency database too; it's a separate table
+	// (postings), not a separate schema
+	ledgerStore := ledger.NewPostgresStore(idempotencyDB)
+
+	walletConn, err := grpc.NewClient(
+		cfg.Services.WalletServiceAddr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+		grpc.WithChainUnaryInterceptor(tracing.UnaryClientInterceptor("wallet-service")),
+	)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("failed to dial wallet service for ledger activities")
+	}
+	defer walletConn.Close()
+
+	ledgerActivities := activity.NewLedgerActivities(ledgerStore, walletpb.NewWalletServiceClient(walletConn), logger)
+
+	// eventBus fans out saga/order lifecycle events to WebSocket subscribers
+	// and backs the gRPC GetOrderStatus RPC with the latest known state
+	eventBus := websocket.NewBus()
+
 	// Create Temporal worker
 	w := worker.New(temporalClient, cfg.Temporal.TaskQueue, worker.Options{
 		MaxConcurrentActivityExecutionSize: cfg.Temporal.MaxConcurrent,
 	})
 
 	// Register workflows
-	w.RegisterWorkflow(workflow.PlaceOrderWorkflow)
+	w.RegisterWorkflow(validatorworkflow.PlaceOrderWorkflow)
+	w.RegisterWorkflow(validatorworkflow.BatchPlaceOrdersWorkflow)
+	w.RegisterWorkflow(validatorworkflow.ScheduledOrderWorkflow)
+	w.RegisterWorkflow(validatorworkflow.CancelUserOrdersWorkflow)
+	w.RegisterWorkflow(validatorworkflow.ReconciliationWorkflow)
 
 	// Register activities
 	w.RegisterActivity(validationActivities.ValidateOrder)
+	w.RegisterActivity(validationActivities.BatchValidateOrders)
 	w.RegisterActivity(walletActivities.ReserveFunds)
 	w.RegisterActivity(walletActivities.CommitReservation)
 	w.RegisterActivity(walletActivities.CancelReservation)
+	w.RegisterActivity(walletActivities.CancelReservationsForUser)
 	w.RegisterActivity(orderBookActivities.PlaceOrderInBook)
 	w.RegisterActivity(orderBookActivities.CancelOrder)
+	w.RegisterActivity(orderBookActivities.RecoverOrders)
+	w.RegisterActivity(orderBookActivities.CancelOrdersForUser)
+	w.RegisterActivity(marketStateActivities.GetMarketState)
+	w.RegisterActivity(marketStateActivities.InvalidateMarketState)
+	w.RegisterActivity(positionActivities.CheckExposure)
+	w.RegisterActivity(idempotencyActivities.ClaimIdempotencyKey)
+	w.RegisterActivity(idempotencyActivities.RecordIdempotencyResult)
+	w.RegisterActivity(fillActivities.RecordFill)
+	w.RegisterActivity(fillActivities.GetCumulativePnL)
+	w.RegisterActivity(ledgerActivities.RecordPosting)
+	w.RegisterActivity(ledgerActivities.GetAccountBalance)
+	w.RegisterActivity(ledgerActivities.GetSagaPostings)
+	w.RegisterActivity(ledgerActivities.ReconcileWithWallet)
 
 	logger.Info().Str("task_queue", cfg.Temporal.TaskQueue).Msg("Temporal worker registered")
 
@@ -86,8 +193,40 @@ func main() {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	// Bridge events published by activities (via eventPublisher) onto eventBus
+	go websocket.BridgeEvents(ctx, eventPublisher, eventBus, logger)
+
+	// Start the saga recovery scanner, which sends resume_saga or compensate_saga
+	// to PlaceOrderWorkflow executions stuck longer than cfg.Recovery.StuckThreshold
+	if cfg.Recovery.Enabled {
+		recoveryManager := recovery.NewRecoveryManager(
+			temporalClient,
+			cfg.Temporal.Namespace,
+			cfg.Recovery.PollInterval,
+			cfg.Recovery.StuckThreshold,
+			logger,
+		)
+		go recoveryManager.Run(ctx)
+	}
+
+	// Start the periodic ledger/wallet-service reconciliation workflow, one
+	// long-lived (ContinueAsNew) execution per deployment
+	if cfg.Ledger.ReconciliationEnabled && len(cfg.Ledger.ReconciliationUserIDs) > 0 {
+		_, err := temporalClient.ExecuteWorkflow(ctx, client.StartWorkflowOptions{
+			ID:        "reconciliation-workflow",
+			TaskQueue: cfg.Temporal.TaskQueue,
+		}, validatorworkflow.ReconciliationWorkflow, validatorworkflow.ReconciliationWorkflowInput{
+			UserIDs:  cfg.Ledger.ReconciliationUserIDs,
+			Currency: cfg.Ledger.ReconciliationCurrency,
+			Interval: cfg.Ledger.ReconciliationInterval,
+		})
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to start reconciliation workflow")
+		}
+	}
+
 	// Start servers
-	errChan := make(chan error, 3)
+	errChan := make(chan error, 4)
 
 	// Start Temporal worker
 	go func() {
@@ -99,13 +238,18 @@ func main() {
 
 	// Start gRPC server
 	go func() {
-		errChan <- startGRPCServer(cfg, temporalClient, logger)
+		errChan <- startGRPCServer(cfg, temporalClient, eventBus, fillRepository, logger)
+	}()
+
+	// Start WebSocket server
+	go func() {
+		errChan <- startWebSocketServer(ctx, cfg, eventBus, logger)
 	}()
 
 	// Start metrics server
 	if cfg.Metrics.Enabled {
 		go func() {
-			errChan <- startMetricsServer(cfg, logger)
+			errChan <- startMetricsServer(cfg, rulesProvider, logger)
 		}()
 	}
 
@@ -132,16 +276,16 @@ func main() {
 }
 
 // startGRPCServer starts the gRPC server
-func startGRPCServer(cfg *config.Config, temporalClient client.Client, logger zerolog.Logger) error {
+func startGRPCServer(cfg *config.Config, temporalClient client.Client, eventBus *websocket.Bus, fillRepository activity.FillRepository, logger zerolog.Logger) error {
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", cfg.Server.GRPCPort))
 	if err != nil {
 		return fmt.Errorf("failed to listen: %w", err)
 	}
 
-	grpcServer := grpc.NewServer()
+	grpcServer := grpc.NewServer(grpc.UnaryInterceptor(grpcHandler.TracingInterceptor))
 
 	// Register services
-	orderHandler := grpcHandler.NewOrderHandler(temporalClient, logger)
+	orderHandler := grpcHandler.NewOrderHandler(temporalClient, eventBus, fillRepository, logger)
 	orderv1.RegisterValidatorServiceServer(grpcServer, orderHandler)
 
 	logger.Info().Int("port", cfg.Server.GRPCPort).Msg("gRPC server listening")
@@ -153,11 +297,44 @@ func startGRPCServer(cfg *config.Config, temporalClient client.Client, logger ze
 	return nil
 }
 
+// startWebSocketServer starts the WebSocket server that streams order/saga
+// lifecycle events to subscribed clients
+func startWebSocketServer(ctx context.Context, cfg *config.Config, eventBus *websocket.Bus, logger zerolog.Logger) error {
+	tokenValidator := websocket.NewHMACTokenValidator(cfg.WebSocket.AuthSharedSecret)
+
+	mux := http.NewServeMux()
+	mux.Handle("/ws/orders", websocket.NewHandler(eventBus, tokenValidator, logger))
+
+	server := &http.Server{
+		Addr:    fmt.Sprintf(":%d", cfg.Server.HTTPPort),
+		Handler: mux,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	logger.Info().Int("port", cfg.Server.HTTPPort).Msg("WebSocket server listening")
+
+	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		return fmt.Errorf("serve websocket: %w", err)
+	}
+
+	return nil
+}
+
 // startMetricsServer starts the Prometheus metrics server
-func startMetricsServer(cfg *config.Config, logger zerolog.Logger) error {
+func startMetricsServer(cfg *config.Config, rulesProvider activity.RulesProvider, logger zerolog.Logger) error {
 	mux := http.NewServeMux()
 	mux.Handle(cfg.Metrics.Path, promhttp.Handler())
 
+	rulesHandler := httpHandler.NewRulesHandler(rulesProvider, logger)
+	mux.HandleFunc("/rules", rulesHandler.GetRules)
+	mux.HandleFunc("/admin/rules", rulesHandler.PushRules)
+
 	server := &http.Server{
 		Addr:    fmt.Sprintf(":%d", cfg.Server.MetricsPort),
 		Handler: mux,
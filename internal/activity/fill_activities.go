@@ -0,0 +1,101 @@
+package activity
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+	"go.temporal.io/sdk/activity"
+
+	"github.com/cypherlabdev/order-validator-service/internal/events"
+	"github.com/cypherlabdev/order-validator-service/internal/models"
+	"github.com/cypherlabdev/order-validator-service/internal/workflow"
+)
+
+// FillActivities implements the settlement/fill-tracking activities invoked
+// once PlaceOrderInBook reports a match
+type FillActivities struct {
+	repository FillRepository
+	publisher  events.Publisher
+	logger     zerolog.Logger
+}
+
+// NewFillActivities creates a new fill activities instance
+func NewFillActivities(repository FillRepository, publisher events.Publisher, logger zerolog.Logger) *FillActivities {
+	return &FillActivities{
+		repository: repository,
+		publisher:  publisher,
+		logger:     logger.With().Str("component", "fill_activities").Logger(),
+	}
+}
+
+// RecordFill persists one matched portion of an order and publishes an
+// order.fill event. It's called once per PlaceOrderInBook response that
+// reports MATCHED or PARTIALLY_FILLED, so a partially-filled order
+// accumulates one Fill row per match rather than a single row updated in place.
+func (a *FillActivities) RecordFill(ctx context.Context, input workflow.RecordFillInput) (*workflow.RecordFillResult, error) {
+	logger := activity.GetLogger(ctx)
+
+	odds, err := decimal.NewFromString(input.MatchedOdds)
+	if err != nil {
+		return nil, fmt.Errorf("parse matched odds: %w", err)
+	}
+
+	stake, err := decimal.NewFromString(input.MatchedStake)
+	if err != nil {
+		return nil, fmt.Errorf("parse matched stake: %w", err)
+	}
+
+	fill := &models.Fill{
+		OrderID:      input.OrderID,
+		SagaID:       input.SagaID,
+		UserID:       input.UserID,
+		MatchID:      input.MatchID,
+		Side:         models.OrderSide(input.Side),
+		MatchedOdds:  odds,
+		MatchedStake: stake,
+		MatchedAt:    time.Now(),
+		// PnL isn't known until settlement; it's recorded as zero here and
+		// updated once a settlement process resolves the event's outcome.
+		PnL: decimal.Zero,
+	}
+
+	if err := a.repository.RecordFill(ctx, fill); err != nil {
+		logger.Error("Failed to record fill", "order_id", input.OrderID, "error", err)
+		return nil, fmt.Errorf("record fill: %w", err)
+	}
+
+	if err := a.publisher.Publish(ctx, events.Event{
+		EventType:    events.EventTypeOrderFilled,
+		SagaID:       input.SagaID,
+		UserID:       input.UserID,
+		OrderID:      input.OrderID,
+		MatchID:      input.MatchID,
+		MatchedOdds:  input.MatchedOdds,
+		MatchedStake: input.MatchedStake,
+		Timestamp:    fill.MatchedAt,
+	}); err != nil {
+		logger.Warn("Failed to publish order.fill event", "order_id", input.OrderID, "error", err)
+	}
+
+	logger.Info("Fill recorded", "order_id", input.OrderID, "fill_id", fill.ID.String(), "match_id", input.MatchID)
+	return &workflow.RecordFillResult{FillID: fill.ID.String()}, nil
+}
+
+// GetCumulativePnL sums recorded (realized) PnL across input.OrderIDs. It
+// backs ScheduledOrderWorkflow's circuit breaker and QueryCumulativePnL
+// query - since PnL is zero until settlement, this only reflects orders
+// that have actually settled, not floating PnL on still-open legs.
+func (a *FillActivities) GetCumulativePnL(ctx context.Context, input workflow.GetCumulativePnLInput) (*workflow.GetCumulativePnLResult, error) {
+	logger := activity.GetLogger(ctx)
+
+	total, err := a.repository.SumPnL(ctx, input.OrderIDs)
+	if err != nil {
+		logger.Error("Failed to sum cumulative pnl", "order_count", len(input.OrderIDs), "error", err)
+		return nil, fmt.Errorf("get cumulative pnl: %w", err)
+	}
+
+	return &workflow.GetCumulativePnLResult{CumulativePnL: total.String()}, nil
+}
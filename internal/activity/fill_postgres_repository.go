@@ -0,0 +1,237 @@
+package activity
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/shopspring/decimal"
+
+	"github.com/cypherlabdev/order-validator-service/internal/models"
+)
+
+// fillCursorZeroAt is the implicit cursor position of an empty/unset cursor -
+// earlier than any real fill's MatchedAt, so it matches every row
+var fillCursorZeroAt = time.Unix(0, 0).UTC()
+
+// PostgresFillRepository is a FillRepository backed by a Postgres table:
+//
+//	CREATE TABLE fills (
+//	    id             UUID PRIMARY KEY,
+//	    order_id       TEXT NOT NULL,
+//	    saga_id        TEXT NOT NULL,
+//	    user_id        TEXT NOT NULL,
+//	    match_id       TEXT,
+//	    side           TEXT NOT NULL,
+//	    matched_odds   NUMERIC NOT NULL,
+//	    matched_stake  NUMERIC NOT NULL,
+//	    matched_at     TIMESTAMPTZ NOT NULL,
+//	    settled_at     TIMESTAMPTZ,
+//	    pnl            NUMERIC NOT NULL DEFAULT 0,
+//	    tx_ref         TEXT
+//	);
+//	CREATE INDEX ON fills (order_id);
+//	CREATE INDEX ON fills (user_id, matched_at, id);
+type PostgresFillRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresFillRepository creates a new Postgres-backed fill repository
+func NewPostgresFillRepository(db *sql.DB) *PostgresFillRepository {
+	return &PostgresFillRepository{db: db}
+}
+
+// RecordFill implements FillRepository. Every call inserts a new row; a
+// partially-filled order accumulates one row per match rather than an
+// existing row being overwritten.
+func (r *PostgresFillRepository) RecordFill(ctx context.Context, fill *models.Fill) error {
+	if fill.ID == uuid.Nil {
+		fill.ID = uuid.New()
+	}
+
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO fills (id, order_id, saga_id, user_id, match_id, side, matched_odds, matched_stake, matched_at, settled_at, pnl, tx_ref)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+	`, fill.ID, fill.OrderID, fill.SagaID, fill.UserID, fill.MatchID, fill.Side,
+		fill.MatchedOdds.String(), fill.MatchedStake.String(), fill.MatchedAt, fill.SettledAt, fill.PnL.String(), fill.TxRef)
+	if err != nil {
+		return fmt.Errorf("record fill: %w", err)
+	}
+
+	return nil
+}
+
+// GetFillsByOrder implements FillRepository
+func (r *PostgresFillRepository) GetFillsByOrder(ctx context.Context, orderID string) ([]*models.Fill, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, order_id, saga_id, user_id, COALESCE(match_id, ''), side, matched_odds, matched_stake, matched_at, settled_at, pnl, COALESCE(tx_ref, '')
+		FROM fills
+		WHERE order_id = $1
+		ORDER BY matched_at, id
+	`, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("get fills by order: %w", err)
+	}
+	defer rows.Close()
+
+	return scanFills(rows)
+}
+
+// GetFillsByUser implements FillRepository using keyset pagination over
+// (matched_at, id), so results stay stable as new fills are appended between pages.
+func (r *PostgresFillRepository) GetFillsByUser(ctx context.Context, userID string, from, to time.Time, cursor string, limit int) ([]*models.Fill, string, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+
+	cursorAt, cursorID, err := decodeFillCursor(cursor)
+	if err != nil {
+		return nil, "", fmt.Errorf("decode cursor: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, order_id, saga_id, user_id, COALESCE(match_id, ''), side, matched_odds, matched_stake, matched_at, settled_at, pnl, COALESCE(tx_ref, '')
+		FROM fills
+		WHERE user_id = $1 AND matched_at >= $2 AND matched_at < $3
+		  AND (matched_at, id) > ($4, $5)
+		ORDER BY matched_at, id
+		LIMIT $6
+	`, userID, from, to, cursorAt, cursorID, limit+1)
+	if err != nil {
+		return nil, "", fmt.Errorf("get fills by user: %w", err)
+	}
+	defer rows.Close()
+
+	fills, err := scanFills(rows)
+	if err != nil {
+		return nil, "", err
+	}
+
+	nextCursor := ""
+	if len(fills) > limit {
+		last := fills[limit-1]
+		nextCursor = encodeFillCursor(last.MatchedAt, last.ID)
+		fills = fills[:limit]
+	}
+
+	return fills, nextCursor, nil
+}
+
+// TotalFilledStake implements FillRepository
+func (r *PostgresFillRepository) TotalFilledStake(ctx context.Context, orderID string) (decimal.Decimal, error) {
+	var total string
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COALESCE(SUM(matched_stake), 0) FROM fills WHERE order_id = $1
+	`, orderID).Scan(&total)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("total filled stake: %w", err)
+	}
+
+	sum, err := decimal.NewFromString(total)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("parse total filled stake: %w", err)
+	}
+
+	return sum, nil
+}
+
+// SumPnL implements FillRepository
+func (r *PostgresFillRepository) SumPnL(ctx context.Context, orderIDs []string) (decimal.Decimal, error) {
+	if len(orderIDs) == 0 {
+		return decimal.Zero, nil
+	}
+
+	var total string
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COALESCE(SUM(pnl), 0) FROM fills WHERE order_id = ANY($1)
+	`, pq.Array(orderIDs)).Scan(&total)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("sum pnl: %w", err)
+	}
+
+	sum, err := decimal.NewFromString(total)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("parse sum pnl: %w", err)
+	}
+
+	return sum, nil
+}
+
+// scanFills reads every row of rows into a slice of Fill, closing none of it
+// (the caller owns rows' lifecycle)
+func scanFills(rows *sql.Rows) ([]*models.Fill, error) {
+	var fills []*models.Fill
+	for rows.Next() {
+		var f models.Fill
+		var side, matchedOdds, matchedStake, pnl string
+
+		if err := rows.Scan(&f.ID, &f.OrderID, &f.SagaID, &f.UserID, &f.MatchID, &side,
+			&matchedOdds, &matchedStake, &f.MatchedAt, &f.SettledAt, &pnl, &f.TxRef); err != nil {
+			return nil, fmt.Errorf("scan fill: %w", err)
+		}
+		f.Side = models.OrderSide(side)
+
+		odds, err := decimal.NewFromString(matchedOdds)
+		if err != nil {
+			return nil, fmt.Errorf("parse matched_odds: %w", err)
+		}
+		f.MatchedOdds = odds
+
+		stake, err := decimal.NewFromString(matchedStake)
+		if err != nil {
+			return nil, fmt.Errorf("parse matched_stake: %w", err)
+		}
+		f.MatchedStake = stake
+
+		pnlDec, err := decimal.NewFromString(pnl)
+		if err != nil {
+			return nil, fmt.Errorf("parse pnl: %w", err)
+		}
+		f.PnL = pnlDec
+
+		fills = append(fills, &f)
+	}
+
+	return fills, rows.Err()
+}
+
+// encodeFillCursor packs the last row of a page into an opaque cursor token
+func encodeFillCursor(at time.Time, id uuid.UUID) string {
+	raw := at.UTC().Format(time.RFC3339Nano) + "|" + id.String()
+	return base64.URLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeFillCursor unpacks a cursor token produced by encodeFillCursor. An
+// empty cursor decodes to fillCursorZeroAt/uuid.Nil, matching every row.
+func decodeFillCursor(cursor string) (time.Time, uuid.UUID, error) {
+	if cursor == "" {
+		return fillCursorZeroAt, uuid.Nil, nil
+	}
+
+	raw, err := base64.URLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, uuid.Nil, fmt.Errorf("invalid cursor encoding: %w", err)
+	}
+
+	parts := strings.SplitN(string(raw), "|", 2)
+	if len(parts) != 2 {
+		return time.Time{}, uuid.Nil, fmt.Errorf("invalid cursor format")
+	}
+
+	at, err := time.Parse(time.RFC3339Nano, parts[0])
+	if err != nil {
+		return time.Time{}, uuid.Nil, fmt.Errorf("invalid cursor timestamp: %w", err)
+	}
+
+	id, err := uuid.Parse(parts[1])
+	if err != nil {
+		return time.Time{}, uuid.Nil, fmt.Errorf("invalid cursor id: %w", err)
+	}
+
+	return at, id, nil
+}
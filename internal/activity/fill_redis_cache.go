@@ -0,0 +1,94 @@
+package activity
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/shopspring/decimal"
+
+	"github.com/cypherlabdev/order-validator-service/internal/models"
+)
+
+// fillCacheTTL bounds how long a cached GetFillsByOrder result is served
+// before falling back to Postgres, trading a little staleness for
+// dramatically cheaper repeat reads of a recently-matched order.
+const fillCacheTTL = 30 * time.Second
+
+// CachedFillRepository wraps a FillRepository with a Redis cache over
+// GetFillsByOrder, the hottest read path (a client polling for its own
+// order's match timeline right after placing it). RecordFill invalidates the
+// cached entry for the order it just appended to rather than patching it.
+type CachedFillRepository struct {
+	inner  FillRepository
+	client *redis.Client
+}
+
+// NewCachedFillRepository wraps inner with a Redis cache reachable at addr
+func NewCachedFillRepository(inner FillRepository, addr string) *CachedFillRepository {
+	return &CachedFillRepository{
+		inner:  inner,
+		client: redis.NewClient(&redis.Options{Addr: addr}),
+	}
+}
+
+// RecordFill implements FillRepository
+func (c *CachedFillRepository) RecordFill(ctx context.Context, fill *models.Fill) error {
+	if err := c.inner.RecordFill(ctx, fill); err != nil {
+		return err
+	}
+
+	// Best effort: a failed invalidation just means the next read is stale
+	// until fillCacheTTL expires, not that it's wrong forever.
+	_ = c.client.Del(ctx, fillCacheKey(fill.OrderID)).Err()
+
+	return nil
+}
+
+// GetFillsByOrder implements FillRepository, serving from cache when present
+func (c *CachedFillRepository) GetFillsByOrder(ctx context.Context, orderID string) ([]*models.Fill, error) {
+	key := fillCacheKey(orderID)
+
+	if cached, err := c.client.Get(ctx, key).Bytes(); err == nil {
+		var fills []*models.Fill
+		if jsonErr := json.Unmarshal(cached, &fills); jsonErr == nil {
+			return fills, nil
+		}
+	}
+
+	fills, err := c.inner.GetFillsByOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if data, err := json.Marshal(fills); err == nil {
+		_ = c.client.Set(ctx, key, data, fillCacheTTL).Err()
+	}
+
+	return fills, nil
+}
+
+// GetFillsByUser implements FillRepository. The from/to/cursor combination
+// has too much cardinality to cache cheaply, and this path is less
+// latency-sensitive than GetFillsByOrder, so it passes straight through.
+func (c *CachedFillRepository) GetFillsByUser(ctx context.Context, userID string, from, to time.Time, cursor string, limit int) ([]*models.Fill, string, error) {
+	return c.inner.GetFillsByUser(ctx, userID, from, to, cursor, limit)
+}
+
+// TotalFilledStake implements FillRepository, passing straight through since
+// it's a cheap aggregate query rather than a repeated hot read.
+func (c *CachedFillRepository) TotalFilledStake(ctx context.Context, orderID string) (decimal.Decimal, error) {
+	return c.inner.TotalFilledStake(ctx, orderID)
+}
+
+// SumPnL implements FillRepository, passing straight through since it spans
+// an arbitrary set of orderIDs rather than a single repeatedly-read key.
+func (c *CachedFillRepository) SumPnL(ctx context.Context, orderIDs []string) (decimal.Decimal, error) {
+	return c.inner.SumPnL(ctx, orderIDs)
+}
+
+func fillCacheKey(orderID string) string {
+	return fmt.Sprintf("fills:order:%s", orderID)
+}
@@ -0,0 +1,35 @@
+package activity
+
+import (
+	"context"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/cypherlabdev/order-validator-service/internal/models"
+)
+
+// FillRepository persists Fill rows and serves the settlement query API.
+// Partial fills are appended as new rows rather than mutated in place, so
+// GetFillsByOrder always returns the full match timeline for an order.
+type FillRepository interface {
+	RecordFill(ctx context.Context, fill *models.Fill) error
+
+	// GetFillsByOrder returns every fill recorded for orderID, in MatchedAt order
+	GetFillsByOrder(ctx context.Context, orderID string) ([]*models.Fill, error)
+
+	// GetFillsByUser returns fills for userID with MatchedAt in [from, to),
+	// paginated by an opaque cursor. An empty cursor starts from the
+	// beginning; the returned nextCursor is empty once there are no more pages.
+	GetFillsByUser(ctx context.Context, userID string, from, to time.Time, cursor string, limit int) (fills []*models.Fill, nextCursor string, err error)
+
+	// TotalFilledStake sums MatchedStake across every fill recorded for
+	// orderID, so a caller can report an order's cumulative filled stake
+	// without summing the full fill list itself.
+	TotalFilledStake(ctx context.Context, orderID string) (decimal.Decimal, error)
+
+	// SumPnL sums PnL across every fill recorded for any of orderIDs. PnL is
+	// zero on a fill until settlement resolves it (see RecordFill), so this
+	// reflects only realized P&L, not floating/unrealized P&L on open orders.
+	SumPnL(ctx context.Context, orderIDs []string) (decimal.Decimal, error)
+}
@@ -0,0 +1,95 @@
+package activity
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog"
+	"go.temporal.io/sdk/activity"
+
+	"github.com/cypherlabdev/order-validator-service/internal/models"
+	"github.com/cypherlabdev/order-validator-service/internal/workflow"
+)
+
+// defaultIdempotencyTTL bounds how long a completed order's result is kept
+// around for replay before its idempotency key can be reused
+const defaultIdempotencyTTL = 24 * time.Hour
+
+// IdempotencyActivities implements the claim/complete activities backing
+// PlaceOrderWorkflow's duplicate-submission protection
+type IdempotencyActivities struct {
+	store  IdempotencyStore
+	ttl    time.Duration
+	logger zerolog.Logger
+}
+
+// NewIdempotencyActivities creates a new idempotency activities instance. A
+// non-positive ttl falls back to defaultIdempotencyTTL.
+func NewIdempotencyActivities(store IdempotencyStore, ttl time.Duration, logger zerolog.Logger) *IdempotencyActivities {
+	if ttl <= 0 {
+		ttl = defaultIdempotencyTTL
+	}
+
+	return &IdempotencyActivities{
+		store:  store,
+		ttl:    ttl,
+		logger: logger.With().Str("component", "idempotency_activities").Logger(),
+	}
+}
+
+// ClaimIdempotencyKey claims (userID, key) for workflowID. If the key has
+// never been seen (or its previous claim has expired), the claim succeeds and
+// the caller should proceed with the saga. Otherwise the caller is told
+// whether the key is still in progress under another workflow, or handed the
+// cached terminal result of the run that already completed it.
+func (a *IdempotencyActivities) ClaimIdempotencyKey(ctx context.Context, input workflow.ClaimIdempotencyKeyInput) (*workflow.ClaimIdempotencyKeyResult, error) {
+	logger := activity.GetLogger(ctx)
+
+	record, err := a.store.Claim(ctx, input.UserID, input.IdempotencyKey, input.WorkflowID, a.ttl)
+	if err != nil {
+		logger.Error("Failed to claim idempotency key", "error", err)
+		return nil, fmt.Errorf("claim idempotency key: %w", err)
+	}
+
+	if record.WorkflowID != input.WorkflowID {
+		if record.Status == models.IdempotencyStatusPending {
+			logger.Warn("Idempotency key already in progress under another workflow",
+				"idempotency_key", input.IdempotencyKey, "existing_workflow_id", record.WorkflowID)
+			return &workflow.ClaimIdempotencyKeyResult{
+				InProgress: true,
+				WorkflowID: record.WorkflowID,
+			}, nil
+		}
+
+		logger.Info("Idempotency key already resolved, returning cached result",
+			"idempotency_key", input.IdempotencyKey, "existing_workflow_id", record.WorkflowID)
+		return &workflow.ClaimIdempotencyKeyResult{
+			WorkflowID:  record.WorkflowID,
+			FinalResult: record.FinalResult,
+		}, nil
+	}
+
+	logger.Info("Idempotency key claimed", "idempotency_key", input.IdempotencyKey)
+	return &workflow.ClaimIdempotencyKeyResult{FirstSeen: true, WorkflowID: input.WorkflowID}, nil
+}
+
+// RecordIdempotencyResult persists the terminal outcome of a saga under its
+// idempotency key, so a duplicate submission can be answered from cache for
+// the life of the TTL.
+func (a *IdempotencyActivities) RecordIdempotencyResult(ctx context.Context, input workflow.RecordIdempotencyResultInput) (*workflow.RecordIdempotencyResultResult, error) {
+	logger := activity.GetLogger(ctx)
+
+	status := models.IdempotencyStatusCompleted
+	if !input.Success {
+		status = models.IdempotencyStatusFailed
+	}
+
+	if err := a.store.Complete(ctx, input.UserID, input.IdempotencyKey, status, input.FinalResult, a.ttl); err != nil {
+		logger.Error("Failed to record idempotency result", "error", err)
+		return nil, fmt.Errorf("record idempotency result: %w", err)
+	}
+
+	logger.Info("Idempotency result recorded", "idempotency_key", input.IdempotencyKey, "status", status)
+	return &workflow.RecordIdempotencyResultResult{}, nil
+}
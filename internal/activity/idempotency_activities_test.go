@@ -0,0 +1,160 @@
+package activity
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cypherlabdev/order-validator-service/internal/workflow"
+)
+
+// setupTestIdempotencyActivity creates idempotency activities backed by a
+// fresh in-memory store with the given TTL
+func setupTestIdempotencyActivity(ttl time.Duration) *IdempotencyActivities {
+	return NewIdempotencyActivities(NewMemoryIdempotencyStore(), ttl, zerolog.Nop())
+}
+
+// TestClaimIdempotencyKey_FirstSeen tests that a never-before-seen key is claimed successfully
+func TestClaimIdempotencyKey_FirstSeen(t *testing.T) {
+	a := setupTestIdempotencyActivity(time.Hour)
+
+	result, err := a.ClaimIdempotencyKey(context.Background(), workflow.ClaimIdempotencyKeyInput{
+		UserID:         "user-1",
+		IdempotencyKey: "key-1",
+		WorkflowID:     "wf-1",
+	})
+
+	assert.NoError(t, err)
+	assert.True(t, result.FirstSeen)
+	assert.False(t, result.InProgress)
+}
+
+// TestClaimIdempotencyKey_SameWorkflowRetry tests that the same workflow
+// re-claiming its own key (e.g. activity retry) is treated as a fresh claim
+func TestClaimIdempotencyKey_SameWorkflowRetry(t *testing.T) {
+	a := setupTestIdempotencyActivity(time.Hour)
+	ctx := context.Background()
+	input := workflow.ClaimIdempotencyKeyInput{UserID: "user-1", IdempotencyKey: "key-1", WorkflowID: "wf-1"}
+
+	_, err := a.ClaimIdempotencyKey(ctx, input)
+	assert.NoError(t, err)
+
+	result, err := a.ClaimIdempotencyKey(ctx, input)
+	assert.NoError(t, err)
+	assert.True(t, result.FirstSeen)
+}
+
+// TestClaimIdempotencyKey_ConcurrentRace tests that when many workflows race
+// to claim the same idempotency key, exactly one wins and the rest are told
+// the key is already in progress
+func TestClaimIdempotencyKey_ConcurrentRace(t *testing.T) {
+	a := setupTestIdempotencyActivity(time.Hour)
+
+	const racers = 20
+	var wg sync.WaitGroup
+	results := make([]*workflow.ClaimIdempotencyKeyResult, racers)
+
+	for i := 0; i < racers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			result, err := a.ClaimIdempotencyKey(context.Background(), workflow.ClaimIdempotencyKeyInput{
+				UserID:         "user-1",
+				IdempotencyKey: "key-race",
+				WorkflowID:     fmt.Sprintf("wf-%d", i),
+			})
+			assert.NoError(t, err)
+			results[i] = result
+		}(i)
+	}
+	wg.Wait()
+
+	firstSeenCount := 0
+	inProgressCount := 0
+	for _, r := range results {
+		if r.FirstSeen {
+			firstSeenCount++
+		}
+		if r.InProgress {
+			inProgressCount++
+		}
+	}
+
+	assert.Equal(t, 1, firstSeenCount)
+	assert.Equal(t, racers-1, inProgressCount)
+}
+
+// TestClaimIdempotencyKey_ReplaysCompletedResult tests that once a key is
+// recorded as completed, a later claim by a different workflow gets the
+// cached result back instead of InProgress or FirstSeen
+func TestClaimIdempotencyKey_ReplaysCompletedResult(t *testing.T) {
+	a := setupTestIdempotencyActivity(time.Hour)
+	ctx := context.Background()
+
+	_, err := a.ClaimIdempotencyKey(ctx, workflow.ClaimIdempotencyKeyInput{
+		UserID:         "user-1",
+		IdempotencyKey: "key-1",
+		WorkflowID:     "wf-1",
+	})
+	assert.NoError(t, err)
+
+	_, err = a.RecordIdempotencyResult(ctx, workflow.RecordIdempotencyResultInput{
+		UserID:         "user-1",
+		IdempotencyKey: "key-1",
+		Success:        true,
+		FinalResult:    `{"OrderID":"order-1","Status":"SETTLED"}`,
+	})
+	assert.NoError(t, err)
+
+	result, err := a.ClaimIdempotencyKey(ctx, workflow.ClaimIdempotencyKeyInput{
+		UserID:         "user-1",
+		IdempotencyKey: "key-1",
+		WorkflowID:     "wf-2",
+	})
+	assert.NoError(t, err)
+	assert.False(t, result.FirstSeen)
+	assert.False(t, result.InProgress)
+	assert.Equal(t, `{"OrderID":"order-1","Status":"SETTLED"}`, result.FinalResult)
+}
+
+// TestClaimIdempotencyKey_ExpiredKeyReuse tests that a completed key can be
+// claimed again by a new workflow once its TTL has elapsed
+func TestClaimIdempotencyKey_ExpiredKeyReuse(t *testing.T) {
+	a := setupTestIdempotencyActivity(10 * time.Millisecond)
+	ctx := context.Background()
+
+	_, err := a.ClaimIdempotencyKey(ctx, workflow.ClaimIdempotencyKeyInput{
+		UserID:         "user-1",
+		IdempotencyKey: "key-1",
+		WorkflowID:     "wf-1",
+	})
+	assert.NoError(t, err)
+
+	_, err = a.RecordIdempotencyResult(ctx, workflow.RecordIdempotencyResultInput{
+		UserID:         "user-1",
+		IdempotencyKey: "key-1",
+		Success:        true,
+		FinalResult:    `{"OrderID":"order-1","Status":"SETTLED"}`,
+	})
+	assert.NoError(t, err)
+
+	time.Sleep(20 * time.Millisecond)
+
+	result, err := a.ClaimIdempotencyKey(ctx, workflow.ClaimIdempotencyKeyInput{
+		UserID:         "user-1",
+		IdempotencyKey: "key-1",
+		WorkflowID:     "wf-2",
+	})
+	assert.NoError(t, err)
+	assert.True(t, result.FirstSeen)
+}
+
+// TestIdempotencyActivities_ImplementsInterface tests that IdempotencyActivities implements the interface
+func TestIdempotencyActivities_ImplementsInterface(t *testing.T) {
+	var _ IdempotencyActivityInterface = (*IdempotencyActivities)(nil)
+}
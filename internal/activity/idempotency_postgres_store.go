@@ -0,0 +1,71 @@
+package activity
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/cypherlabdev/order-validator-service/internal/models"
+)
+
+// PostgresIdempotencyStore is an IdempotencyStore backed by a Postgres table:
+//
+//	CREATE TABLE idempotency_records (
+//	    user_id         TEXT NOT NULL,
+//	    idempotency_key TEXT NOT NULL,
+//	    workflow_id     TEXT NOT NULL,
+//	    status          TEXT NOT NULL,
+//	    final_result    TEXT,
+//	    created_at      TIMESTAMPTZ NOT NULL,
+//	    expires_at      TIMESTAMPTZ NOT NULL,
+//	    PRIMARY KEY (user_id, idempotency_key)
+//	);
+type PostgresIdempotencyStore struct {
+	db *sql.DB
+}
+
+// NewPostgresIdempotencyStore creates a new Postgres-backed idempotency store
+func NewPostgresIdempotencyStore(db *sql.DB) *PostgresIdempotencyStore {
+	return &PostgresIdempotencyStore{db: db}
+}
+
+// Claim implements IdempotencyStore. The upsert only replaces an existing row
+// once its expiry has passed, so a live PENDING or terminal record is always
+// returned unchanged to the caller.
+func (s *PostgresIdempotencyStore) Claim(ctx context.Context, userID, key, workflowID string, ttl time.Duration) (*models.IdempotencyRecord, error) {
+	now := time.Now()
+
+	var record models.IdempotencyRecord
+	err := s.db.QueryRowContext(ctx, `
+		INSERT INTO idempotency_records (user_id, idempotency_key, workflow_id, status, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (user_id, idempotency_key) DO UPDATE SET
+			workflow_id  = CASE WHEN idempotency_records.expires_at < $5 THEN EXCLUDED.workflow_id ELSE idempotency_records.workflow_id END,
+			status       = CASE WHEN idempotency_records.expires_at < $5 THEN EXCLUDED.status ELSE idempotency_records.status END,
+			final_result = CASE WHEN idempotency_records.expires_at < $5 THEN NULL ELSE idempotency_records.final_result END,
+			created_at   = CASE WHEN idempotency_records.expires_at < $5 THEN EXCLUDED.created_at ELSE idempotency_records.created_at END,
+			expires_at   = CASE WHEN idempotency_records.expires_at < $5 THEN EXCLUDED.expires_at ELSE idempotency_records.expires_at END
+		RETURNING user_id, idempotency_key, workflow_id, status, COALESCE(final_result, ''), created_at, expires_at
+	`, userID, key, workflowID, models.IdempotencyStatusPending, now, now.Add(ttl)).
+		Scan(&record.UserID, &record.IdempotencyKey, &record.WorkflowID, &record.Status, &record.FinalResult, &record.CreatedAt, &record.ExpiresAt)
+	if err != nil {
+		return nil, fmt.Errorf("claim idempotency key: %w", err)
+	}
+
+	return &record, nil
+}
+
+// Complete implements IdempotencyStore
+func (s *PostgresIdempotencyStore) Complete(ctx context.Context, userID, key string, status models.IdempotencyStatus, finalResult string, ttl time.Duration) error {
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE idempotency_records
+		SET status = $3, final_result = $4, expires_at = $5
+		WHERE user_id = $1 AND idempotency_key = $2
+	`, userID, key, status, finalResult, time.Now().Add(ttl))
+	if err != nil {
+		return fmt.Errorf("complete idempotency key: %w", err)
+	}
+
+	return nil
+}
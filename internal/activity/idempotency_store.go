@@ -0,0 +1,86 @@
+package activity
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/cypherlabdev/order-validator-service/internal/models"
+)
+
+// IdempotencyStore persists idempotency records keyed by (UserID,
+// IdempotencyKey), allowing IdempotencyActivities to detect and safely
+// respond to duplicate order submissions
+type IdempotencyStore interface {
+	// Claim inserts a PENDING record for (userID, key) tied to workflowID if
+	// none exists, or if the existing one has expired. If a live record
+	// already exists it is returned unchanged, letting the caller tell a
+	// fresh claim apart from a replay.
+	Claim(ctx context.Context, userID, key, workflowID string, ttl time.Duration) (*models.IdempotencyRecord, error)
+	// Complete overwrites the record for (userID, key) with a terminal status
+	// and result, resetting its expiry to ttl from now.
+	Complete(ctx context.Context, userID, key string, status models.IdempotencyStatus, finalResult string, ttl time.Duration) error
+}
+
+// idempotencyStoreKey builds the composite map/row key for a user+idempotency-key pair
+func idempotencyStoreKey(userID, key string) string {
+	return userID + ":" + key
+}
+
+// MemoryIdempotencyStore is an in-memory IdempotencyStore, suitable for tests
+// and single-process deployments
+type MemoryIdempotencyStore struct {
+	mu      sync.Mutex
+	records map[string]*models.IdempotencyRecord
+}
+
+// NewMemoryIdempotencyStore creates a new in-memory idempotency store
+func NewMemoryIdempotencyStore() *MemoryIdempotencyStore {
+	return &MemoryIdempotencyStore{
+		records: make(map[string]*models.IdempotencyRecord),
+	}
+}
+
+// Claim implements IdempotencyStore
+func (s *MemoryIdempotencyStore) Claim(ctx context.Context, userID, key, workflowID string, ttl time.Duration) (*models.IdempotencyRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	storeKey := idempotencyStoreKey(userID, key)
+
+	if existing, ok := s.records[storeKey]; ok && !existing.Expired(now) {
+		return existing, nil
+	}
+
+	record := &models.IdempotencyRecord{
+		UserID:         userID,
+		IdempotencyKey: key,
+		WorkflowID:     workflowID,
+		Status:         models.IdempotencyStatusPending,
+		CreatedAt:      now,
+		ExpiresAt:      now.Add(ttl),
+	}
+	s.records[storeKey] = record
+
+	return record, nil
+}
+
+// Complete implements IdempotencyStore
+func (s *MemoryIdempotencyStore) Complete(ctx context.Context, userID, key string, status models.IdempotencyStatus, finalResult string, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	storeKey := idempotencyStoreKey(userID, key)
+	record, ok := s.records[storeKey]
+	if !ok {
+		record = &models.IdempotencyRecord{UserID: userID, IdempotencyKey: key, CreatedAt: time.Now()}
+		s.records[storeKey] = record
+	}
+
+	record.Status = status
+	record.FinalResult = finalResult
+	record.ExpiresAt = time.Now().Add(ttl)
+
+	return nil
+}
@@ -9,6 +9,7 @@ import (
 // ValidationActivityInterface defines the interface for validation activities
 type ValidationActivityInterface interface {
 	ValidateOrder(ctx context.Context, input workflow.ValidateOrderInput) (*workflow.ValidationResult, error)
+	BatchValidateOrders(ctx context.Context, input workflow.BatchValidateOrdersInput) (*workflow.BatchValidationResult, error)
 }
 
 // WalletActivityInterface defines the interface for wallet activities
@@ -16,10 +17,44 @@ type WalletActivityInterface interface {
 	ReserveFunds(ctx context.Context, input workflow.ReserveFundsInput) (*workflow.ReserveFundsResult, error)
 	CommitReservation(ctx context.Context, input workflow.CommitReservationInput) (*workflow.CommitReservationResult, error)
 	CancelReservation(ctx context.Context, input workflow.CancelReservationInput) (*workflow.CancelReservationResult, error)
+	CancelReservationsForUser(ctx context.Context, input workflow.CancelReservationsForUserInput) (*workflow.CancelReservationsForUserResult, error)
 }
 
 // OrderBookActivityInterface defines the interface for order book activities
 type OrderBookActivityInterface interface {
 	PlaceOrderInBook(ctx context.Context, input workflow.PlaceOrderInBookInput) (*workflow.PlaceOrderInBookResult, error)
 	CancelOrder(ctx context.Context, input workflow.CancelOrderInput) (*workflow.CancelOrderResult, error)
+	RecoverOrders(ctx context.Context, input workflow.RecoverOrdersInput) (*workflow.RecoverOrdersResult, error)
+	CancelOrdersForUser(ctx context.Context, input workflow.CancelOrdersForUserInput) (*workflow.CancelOrdersForUserResult, error)
+}
+
+// MarketStateActivityInterface defines the interface for market state activities
+type MarketStateActivityInterface interface {
+	GetMarketState(ctx context.Context, input workflow.GetMarketStateInput) (*workflow.GetMarketStateResult, error)
+	InvalidateMarketState(ctx context.Context, input workflow.InvalidateMarketStateInput) (*workflow.InvalidateMarketStateResult, error)
+}
+
+// PositionActivityInterface defines the interface for position/exposure activities
+type PositionActivityInterface interface {
+	CheckExposure(ctx context.Context, input workflow.CheckExposureInput) (*workflow.CheckExposureResult, error)
+}
+
+// IdempotencyActivityInterface defines the interface for idempotency-key activities
+type IdempotencyActivityInterface interface {
+	ClaimIdempotencyKey(ctx context.Context, input workflow.ClaimIdempotencyKeyInput) (*workflow.ClaimIdempotencyKeyResult, error)
+	RecordIdempotencyResult(ctx context.Context, input workflow.RecordIdempotencyResultInput) (*workflow.RecordIdempotencyResultResult, error)
+}
+
+// FillActivityInterface defines the interface for settlement/fill-tracking activities
+type FillActivityInterface interface {
+	RecordFill(ctx context.Context, input workflow.RecordFillInput) (*workflow.RecordFillResult, error)
+	GetCumulativePnL(ctx context.Context, input workflow.GetCumulativePnLInput) (*workflow.GetCumulativePnLResult, error)
+}
+
+// LedgerActivityInterface defines the interface for double-entry ledger activities
+type LedgerActivityInterface interface {
+	RecordPosting(ctx context.Context, input workflow.RecordPostingInput) (*workflow.RecordPostingResult, error)
+	GetAccountBalance(ctx context.Context, input workflow.GetAccountBalanceInput) (*workflow.GetAccountBalanceResult, error)
+	GetSagaPostings(ctx context.Context, input workflow.GetSagaPostingsInput) (*workflow.GetSagaPostingsResult, error)
+	ReconcileWithWallet(ctx context.Context, input workflow.ReconcileWithWalletInput) (*workflow.ReconcileWithWalletResult, error)
 }
@@ -0,0 +1,190 @@
+package activity
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+	"go.temporal.io/sdk/activity"
+
+	walletpb "github.com/cypherlabdev/cypherlabdev-protos/gen/go/wallet/v1"
+	"github.com/cypherlabdev/order-validator-service/internal/ledger"
+	"github.com/cypherlabdev/order-validator-service/internal/workflow"
+)
+
+// LedgerActivities implements the double-entry posting and reconciliation
+// activities backing the ledger - an auditable, replayable record of every
+// saga-driven balance movement, kept independent of wallet-service's own
+// bookkeeping so ReconciliationWorkflow has something authoritative-but-local
+// to compare wallet-service against.
+type LedgerActivities struct {
+	store        ledger.Store
+	walletClient walletpb.WalletServiceClient
+	logger       zerolog.Logger
+}
+
+// NewLedgerActivities creates a new ledger activities instance
+func NewLedgerActivities(store ledger.Store, walletClient walletpb.WalletServiceClient, logger zerolog.Logger) *LedgerActivities {
+	return &LedgerActivities{
+		store:        store,
+		walletClient: walletClient,
+		logger:       logger.With().Str("component", "ledger_activities").Logger(),
+	}
+}
+
+// RecordPosting appends one double-entry posting. It's idempotent on
+// (SagaID, IdempotencyKey), so a retried call after a transient failure
+// re-returns the original posting rather than double-booking it.
+func (a *LedgerActivities) RecordPosting(ctx context.Context, input workflow.RecordPostingInput) (*workflow.RecordPostingResult, error) {
+	logger := activity.GetLogger(ctx)
+
+	amount, err := decimal.NewFromString(input.Amount)
+	if err != nil {
+		return nil, fmt.Errorf("parse posting amount: %w", err)
+	}
+
+	posting := &ledger.Posting{
+		SagaID:         input.SagaID,
+		IdempotencyKey: input.IdempotencyKey,
+		DebitAccount:   input.DebitAccount,
+		CreditAccount:  input.CreditAccount,
+		Amount:         amount,
+		Currency:       input.Currency,
+		Reason:         input.Reason,
+		CausalRef:      input.CausalRef,
+	}
+
+	if err := a.store.RecordPosting(ctx, posting); err != nil {
+		logger.Error("Failed to record ledger posting", "saga_id", input.SagaID, "reason", input.Reason, "error", err)
+		return nil, fmt.Errorf("record posting: %w", err)
+	}
+
+	logger.Info("Ledger posting recorded", "posting_id", posting.ID.String(),
+		"debit_account", input.DebitAccount, "credit_account", input.CreditAccount, "amount", input.Amount)
+
+	return &workflow.RecordPostingResult{PostingID: posting.ID.String()}, nil
+}
+
+// GetAccountBalance returns the ledger's own projected balance for an
+// account, computed from every posting recorded against it
+func (a *LedgerActivities) GetAccountBalance(ctx context.Context, input workflow.GetAccountBalanceInput) (*workflow.GetAccountBalanceResult, error) {
+	logger := activity.GetLogger(ctx)
+
+	balance, err := a.store.GetAccountBalance(ctx, input.Account)
+	if err != nil {
+		logger.Error("Failed to get ledger account balance", "account", input.Account, "error", err)
+		return nil, fmt.Errorf("get account balance: %w", err)
+	}
+
+	return &workflow.GetAccountBalanceResult{Balance: balance.String()}, nil
+}
+
+// GetSagaPostings returns every posting recorded for a saga, in the order
+// they were written. It's the durable counterpart to PlaceOrderWorkflow's
+// own ledger_postings query, which only answers for a saga still running.
+func (a *LedgerActivities) GetSagaPostings(ctx context.Context, input workflow.GetSagaPostingsInput) (*workflow.GetSagaPostingsResult, error) {
+	logger := activity.GetLogger(ctx)
+
+	postings, err := a.store.GetSagaPostings(ctx, input.SagaID)
+	if err != nil {
+		logger.Error("Failed to get saga postings", "saga_id", input.SagaID, "error", err)
+		return nil, fmt.Errorf("get saga postings: %w", err)
+	}
+
+	summaries := make([]*workflow.PostingSummary, len(postings))
+	for i, p := range postings {
+		summaries[i] = &workflow.PostingSummary{
+			PostingID:     p.ID.String(),
+			DebitAccount:  p.DebitAccount,
+			CreditAccount: p.CreditAccount,
+			Amount:        p.Amount.String(),
+			Currency:      p.Currency,
+			Reason:        p.Reason,
+			CausalRef:     p.CausalRef,
+		}
+	}
+
+	return &workflow.GetSagaPostingsResult{Postings: summaries}, nil
+}
+
+// ReconcileWithWallet compares the ledger-projected balance for a user's
+// available account in Currency against wallet-service's own authoritative
+// balance, reporting a mismatch as a drift. wallet-service's GetBalance RPC
+// only reports the user's spendable balance, not a separate reserved figure,
+// so reserved-account drift isn't observable this way; a persistent drift
+// here doesn't by itself say which side is wrong, only that this ledger and
+// wallet-service have diverged and it's worth an operator looking.
+func (a *LedgerActivities) ReconcileWithWallet(ctx context.Context, input workflow.ReconcileWithWalletInput) (*workflow.ReconcileWithWalletResult, error) {
+	logger := activity.GetLogger(ctx)
+
+	walletResp, err := a.walletClient.GetBalance(ctx, &walletpb.GetBalanceRequest{
+		UserId:   input.UserID,
+		Currency: input.Currency,
+	})
+	if err != nil {
+		logger.Error("Failed to fetch wallet-service balance", "user_id", input.UserID, "error", err)
+		return nil, fmt.Errorf("get wallet balance: %w", err)
+	}
+
+	checks := []struct {
+		account       string
+		walletBalance string
+	}{
+		{ledger.UserAvailableAccount(input.UserID, input.Currency), walletResp.Balance},
+	}
+
+	result := &workflow.ReconcileWithWalletResult{}
+	for _, check := range checks {
+		walletBalance, err := decimal.NewFromString(check.walletBalance)
+		if err != nil {
+			return nil, fmt.Errorf("parse wallet balance for %s: %w", check.account, err)
+		}
+
+		seen, err := a.store.HasPostings(ctx, check.account)
+		if err != nil {
+			logger.Error("Failed to check ledger posting history during reconciliation", "account", check.account, "error", err)
+			return nil, fmt.Errorf("check postings for %s: %w", check.account, err)
+		}
+		if !seen {
+			// First time this account has ever been reconciled - back-fill an
+			// opening-balance posting for wallet-service's current balance so
+			// it isn't mistaken for drift, then skip comparison this pass.
+			if err := a.store.RecordPosting(ctx, &ledger.Posting{
+				SagaID:         fmt.Sprintf("reconcile-opening-balance-%s", check.account),
+				IdempotencyKey: "opening-balance",
+				DebitAccount:   ledger.OpeningBalanceAccount(input.Currency),
+				CreditAccount:  check.account,
+				Amount:         walletBalance,
+				Currency:       input.Currency,
+				Reason:         "opening balance backfill on first reconciliation",
+			}); err != nil {
+				logger.Error("Failed to record opening-balance posting", "account", check.account, "error", err)
+				return nil, fmt.Errorf("record opening balance for %s: %w", check.account, err)
+			}
+			logger.Info("Recorded opening-balance posting for first-seen account", "account", check.account, "wallet_balance", check.walletBalance)
+			continue
+		}
+
+		ledgerBalance, err := a.store.GetAccountBalance(ctx, check.account)
+		if err != nil {
+			logger.Error("Failed to get ledger balance during reconciliation", "account", check.account, "error", err)
+			return nil, fmt.Errorf("get ledger balance for %s: %w", check.account, err)
+		}
+
+		if !ledgerBalance.Equal(walletBalance) {
+			result.Drifts = append(result.Drifts, &workflow.AccountDrift{
+				Account:       check.account,
+				LedgerBalance: ledgerBalance.String(),
+				WalletBalance: walletBalance.String(),
+				Difference:    ledgerBalance.Sub(walletBalance).String(),
+			})
+		}
+	}
+
+	if len(result.Drifts) > 0 {
+		logger.Warn("Reconciliation found ledger/wallet drift", "user_id", input.UserID, "currency", input.Currency, "drift_count", len(result.Drifts))
+	}
+
+	return result, nil
+}
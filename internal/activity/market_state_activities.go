@@ -0,0 +1,138 @@
+package activity
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.temporal.io/sdk/activity"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	orderbookpb "github.com/cypherlabdev/cypherlabdev-protos/gen/go/orderbook/v1"
+	"github.com/cypherlabdev/order-validator-service/internal/models"
+	"github.com/cypherlabdev/order-validator-service/internal/tracing"
+	"github.com/cypherlabdev/order-validator-service/internal/workflow"
+)
+
+// marketStateCacheTTL bounds how long a cached market state is trusted
+// before the activity re-fetches it from upstream
+const marketStateCacheTTL = 2 * time.Second
+
+// cachedMarketState is a market state entry with its cache expiry
+type cachedMarketState struct {
+	state     *models.MarketState
+	expiresAt time.Time
+}
+
+// MarketStateActivities implements market status and mark-price gating
+// activities used to reject orders against non-tradable or stale markets
+type MarketStateActivities struct {
+	orderBookClient orderbookpb.OrderBookServiceClient
+	logger          zerolog.Logger
+
+	mu    sync.RWMutex
+	cache map[string]cachedMarketState
+}
+
+// NewMarketStateActivities creates a new market state activities instance
+func NewMarketStateActivities(orderBookServiceAddr string, logger zerolog.Logger) (*MarketStateActivities, error) {
+	conn, err := grpc.NewClient(
+		orderBookServiceAddr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+		grpc.WithChainUnaryInterceptor(tracing.UnaryClientInterceptor("order-book-service")),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to order-book-service: %w", err)
+	}
+
+	client := orderbookpb.NewOrderBookServiceClient(conn)
+
+	return &MarketStateActivities{
+		orderBookClient: client,
+		logger:          logger.With().Str("component", "market_state_activities").Logger(),
+		cache:           make(map[string]cachedMarketState),
+	}, nil
+}
+
+// GetMarketState returns the current status, best bid/ask, and mark price
+// for a market, serving from a short-TTL in-memory cache to avoid hammering
+// upstream on high-frequency validation
+func (a *MarketStateActivities) GetMarketState(ctx context.Context, input workflow.GetMarketStateInput) (*workflow.GetMarketStateResult, error) {
+	logger := activity.GetLogger(ctx)
+
+	if cached, ok := a.lookupCache(input.MarketID); ok {
+		return &workflow.GetMarketStateResult{State: cached}, nil
+	}
+
+	req := &orderbookpb.GetMarketStateRequest{MarketId: input.MarketID}
+	resp, err := a.orderBookClient.GetMarketState(ctx, req)
+	if err != nil {
+		logger.Error("Failed to fetch market state", "market_id", input.MarketID, "error", err)
+		return nil, fmt.Errorf("get market state: %w", err)
+	}
+
+	markPrice, err := decimal.NewFromString(resp.MarkPrice)
+	if err != nil {
+		return nil, fmt.Errorf("parse mark price: %w", err)
+	}
+	bestBid, err := decimal.NewFromString(resp.BestBid)
+	if err != nil {
+		return nil, fmt.Errorf("parse best bid: %w", err)
+	}
+	bestAsk, err := decimal.NewFromString(resp.BestAsk)
+	if err != nil {
+		return nil, fmt.Errorf("parse best ask: %w", err)
+	}
+
+	state := &models.MarketState{
+		MarketID:  input.MarketID,
+		Status:    models.MarketStatus(resp.Status),
+		BestBid:   bestBid,
+		BestAsk:   bestAsk,
+		MarkPrice: markPrice,
+	}
+
+	a.storeCache(input.MarketID, state)
+
+	return &workflow.GetMarketStateResult{State: state}, nil
+}
+
+// InvalidateMarketState evicts a market's cached state, forcing the next
+// GetMarketState call to re-fetch from upstream. Workflows call this in
+// response to a market-update signal so stale state is never served longer
+// than necessary.
+func (a *MarketStateActivities) InvalidateMarketState(ctx context.Context, input workflow.InvalidateMarketStateInput) (*workflow.InvalidateMarketStateResult, error) {
+	a.mu.Lock()
+	_, existed := a.cache[input.MarketID]
+	delete(a.cache, input.MarketID)
+	a.mu.Unlock()
+
+	return &workflow.InvalidateMarketStateResult{Invalidated: existed}, nil
+}
+
+func (a *MarketStateActivities) lookupCache(marketID string) (*models.MarketState, bool) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	entry, ok := a.cache[marketID]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return nil, false
+	}
+	return entry.state, true
+}
+
+func (a *MarketStateActivities) storeCache(marketID string, state *models.MarketState) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.cache[marketID] = cachedMarketState{
+		state:     state,
+		expiresAt: time.Now().Add(marketStateCacheTTL),
+	}
+}
@@ -0,0 +1,73 @@
+package activity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cypherlabdev/order-validator-service/internal/models"
+)
+
+// TestMarketStateActivities_ImplementsInterface tests that MarketStateActivities implements the interface
+func TestMarketStateActivities_ImplementsInterface(t *testing.T) {
+	var _ MarketStateActivityInterface = (*MarketStateActivities)(nil)
+}
+
+// TestMarketStateActivities_CacheLookupMiss tests that an empty cache reports a miss
+func TestMarketStateActivities_CacheLookupMiss(t *testing.T) {
+	a := &MarketStateActivities{cache: make(map[string]cachedMarketState)}
+
+	state, ok := a.lookupCache("market_456")
+	assert.False(t, ok)
+	assert.Nil(t, state)
+}
+
+// TestMarketStateActivities_CacheStoreAndLookup tests that a stored entry is served until it expires
+func TestMarketStateActivities_CacheStoreAndLookup(t *testing.T) {
+	a := &MarketStateActivities{cache: make(map[string]cachedMarketState)}
+
+	want := &models.MarketState{
+		MarketID:  "market_456",
+		Status:    models.MarketStatusOpen,
+		MarkPrice: decimal.NewFromFloat(2.5),
+	}
+	a.storeCache("market_456", want)
+
+	got, ok := a.lookupCache("market_456")
+	assert.True(t, ok)
+	assert.Equal(t, want, got)
+}
+
+// TestMarketStateActivities_CacheExpiry tests that an expired entry is treated as a miss
+func TestMarketStateActivities_CacheExpiry(t *testing.T) {
+	a := &MarketStateActivities{cache: make(map[string]cachedMarketState)}
+
+	a.mu.Lock()
+	a.cache["market_456"] = cachedMarketState{
+		state:     &models.MarketState{MarketID: "market_456"},
+		expiresAt: time.Now().Add(-time.Second),
+	}
+	a.mu.Unlock()
+
+	state, ok := a.lookupCache("market_456")
+	assert.False(t, ok)
+	assert.Nil(t, state)
+}
+
+// TestMarketStateActivities_MarketStatuses tests the known market status values
+func TestMarketStateActivities_MarketStatuses(t *testing.T) {
+	statuses := []models.MarketStatus{
+		models.MarketStatusOpen,
+		models.MarketStatusSuspended,
+		models.MarketStatusClosed,
+		models.MarketStatusInAuction,
+	}
+
+	for _, status := range statuses {
+		t.Run(string(status), func(t *testing.T) {
+			assert.NotEmpty(t, status)
+		})
+	}
+}
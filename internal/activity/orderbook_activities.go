@@ -3,29 +3,40 @@ package activity
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/rs/zerolog"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	"go.temporal.io/sdk/activity"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 
 	orderbookpb "github.com/cypherlabdev/cypherlabdev-protos/gen/go/orderbook/v1"
+	"github.com/cypherlabdev/order-validator-service/internal/batch"
+	"github.com/cypherlabdev/order-validator-service/internal/events"
+	"github.com/cypherlabdev/order-validator-service/internal/tracing"
 	"github.com/cypherlabdev/order-validator-service/internal/workflow"
 )
 
 // OrderBookActivities implements order-book-related activities
 type OrderBookActivities struct {
 	orderBookClient orderbookpb.OrderBookServiceClient
+	publisher       events.Publisher
 	logger          zerolog.Logger
 }
 
 // NewOrderBookActivities creates a new order-book activities instance
-func NewOrderBookActivities(orderBookServiceAddr string, logger zerolog.Logger) (*OrderBookActivities, error) {
+func NewOrderBookActivities(orderBookServiceAddr string, publisher events.Publisher, logger zerolog.Logger) (*OrderBookActivities, error) {
 	// Connect to order-book-service
 	conn, err := grpc.NewClient(
 		orderBookServiceAddr,
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+		grpc.WithChainUnaryInterceptor(tracing.UnaryClientInterceptor("order-book-service")),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to order-book-service: %w", err)
@@ -35,12 +46,21 @@ func NewOrderBookActivities(orderBookServiceAddr string, logger zerolog.Logger)
 
 	return &OrderBookActivities{
 		orderBookClient: client,
+		publisher:       publisher,
 		logger:          logger.With().Str("component", "orderbook_activities").Logger(),
 	}, nil
 }
 
 // PlaceOrderInBookActivity places an order in the order book
 func (a *OrderBookActivities) PlaceOrderInBook(ctx context.Context, input workflow.PlaceOrderInBookInput) (*workflow.PlaceOrderInBookResult, error) {
+	ctx, span := tracer.Start(ctx, "activity.PlaceOrderInBook",
+		trace.WithAttributes(
+			attribute.String("saga_id", input.SagaID),
+			attribute.String("reservation_id", input.ReservationID),
+		))
+	defer span.End()
+	ctx = tracing.WithSagaIDBaggage(ctx, input.SagaID)
+
 	logger := activity.GetLogger(ctx)
 	logger.Info("PlaceOrderInBook activity started", "event_id", input.EventID, "market_id", input.MarketID)
 
@@ -60,10 +80,13 @@ func (a *OrderBookActivities) PlaceOrderInBook(ctx context.Context, input workfl
 	resp, err := a.orderBookClient.PlaceBet(ctx, req)
 	if err != nil {
 		logger.Error("Failed to place order in book", "error", err)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
 		return nil, fmt.Errorf("place order in book: %w", err)
 	}
 
 	logger.Info("Order placed in book successfully", "order_id", resp.OrderId, "status", resp.Status)
+	span.SetAttributes(attribute.String("order_id", resp.OrderId))
 
 	// Determine match status from response
 	matchID := ""
@@ -71,6 +94,17 @@ func (a *OrderBookActivities) PlaceOrderInBook(ctx context.Context, input workfl
 		matchID = resp.MatchId
 	}
 
+	if err := a.publisher.Publish(ctx, events.Event{
+		EventType: events.EventTypeOrderPlaced,
+		SagaID:    input.SagaID,
+		UserID:    input.UserID,
+		OrderID:   resp.OrderId,
+		MatchID:   matchID,
+		Timestamp: time.Now(),
+	}); err != nil {
+		logger.Warn("Failed to publish ORDER_PLACED event", "error", err)
+	}
+
 	return &workflow.PlaceOrderInBookResult{
 		OrderID: resp.OrderId,
 		MatchID: matchID,
@@ -80,6 +114,15 @@ func (a *OrderBookActivities) PlaceOrderInBook(ctx context.Context, input workfl
 
 // CancelOrderActivity cancels an order in the order book
 func (a *OrderBookActivities) CancelOrder(ctx context.Context, input workflow.CancelOrderInput) (*workflow.CancelOrderResult, error) {
+	ctx, span := tracer.Start(ctx, "activity.CancelOrder",
+		trace.WithAttributes(
+			attribute.String("saga_id", input.SagaID),
+			attribute.String("order_id", input.OrderID),
+		))
+	defer span.End()
+	ctx = tracing.WithSagaIDBaggage(ctx, input.SagaID)
+	span.AddEvent("saga.compensation_started", trace.WithAttributes(attribute.String("order_id", input.OrderID)))
+
 	logger := activity.GetLogger(ctx)
 	logger.Info("CancelOrder activity started", "order_id", input.OrderID)
 
@@ -92,12 +135,113 @@ func (a *OrderBookActivities) CancelOrder(ctx context.Context, input workflow.Ca
 	resp, err := a.orderBookClient.CancelBet(ctx, req)
 	if err != nil {
 		logger.Error("Failed to cancel order", "error", err)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
 		return nil, fmt.Errorf("cancel order: %w", err)
 	}
 
 	logger.Info("Order cancelled successfully", "status", resp.Status)
 
+	if err := a.publisher.Publish(ctx, events.Event{
+		EventType: events.EventTypeSagaFailed,
+		SagaID:    input.SagaID,
+		OrderID:   input.OrderID,
+		Reason:    "order cancelled during saga compensation",
+		Timestamp: time.Now(),
+	}); err != nil {
+		logger.Warn("Failed to publish SAGA_FAILED event", "error", err)
+	}
+
 	return &workflow.CancelOrderResult{
 		Status: resp.Status,
 	}, nil
 }
+
+// RecoverOrders queries the order-book for input's current outstanding
+// open risk, so a long-running workflow like ScheduledOrderWorkflow can
+// reconcile its in-memory view of outstanding legs against the order-book's
+// own record of them after a worker restart/replay.
+func (a *OrderBookActivities) RecoverOrders(ctx context.Context, input workflow.RecoverOrdersInput) (*workflow.RecoverOrdersResult, error) {
+	logger := activity.GetLogger(ctx)
+	logger.Info("RecoverOrders activity started", "user_id", input.UserID, "market_id", input.MarketID, "selection_id", input.SelectionID)
+
+	resp, err := a.orderBookClient.GetOpenExposure(ctx, &orderbookpb.GetOpenExposureRequest{
+		UserId:      input.UserID,
+		MarketId:    input.MarketID,
+		SelectionId: input.SelectionID,
+	})
+	if err != nil {
+		logger.Error("Failed to fetch open exposure for recovery", "error", err)
+		return nil, fmt.Errorf("recover orders: %w", err)
+	}
+
+	return &workflow.RecoverOrdersResult{OpenRisk: resp.OpenRisk}, nil
+}
+
+// cancelOrdersForUserConcurrency bounds how many CancelBet calls
+// CancelOrdersForUser makes at once
+const cancelOrdersForUserConcurrency = 10
+
+// CancelOrdersForUser lists every order the order-book still considers live
+// for the user (optionally narrowed by Currency/MarketID) and cancels each
+// one concurrently. An order that has already settled or was already
+// cancelled is reported as such rather than failing the whole activity, so
+// CancelUserOrdersWorkflow can report a true partial-success status list
+// without rolling back the cancellations that did succeed.
+func (a *OrderBookActivities) CancelOrdersForUser(ctx context.Context, input workflow.CancelOrdersForUserInput) (*workflow.CancelOrdersForUserResult, error) {
+	ctx, span := tracer.Start(ctx, "activity.CancelOrdersForUser",
+		trace.WithAttributes(attribute.String("saga_id", input.SagaID), attribute.String("user_id", input.UserID)))
+	defer span.End()
+	ctx = tracing.WithSagaIDBaggage(ctx, input.SagaID)
+
+	logger := activity.GetLogger(ctx)
+	logger.Info("CancelOrdersForUser activity started", "user_id", input.UserID, "currency", input.Currency, "market_id", input.MarketID)
+
+	listResp, err := a.orderBookClient.ListOpenOrders(ctx, &orderbookpb.ListOpenOrdersRequest{
+		UserId:   input.UserID,
+		Currency: input.Currency,
+		MarketId: input.MarketID,
+	})
+	if err != nil {
+		logger.Error("Failed to list open orders for user", "error", err)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, fmt.Errorf("list open orders for user: %w", err)
+	}
+
+	statuses := make([]*workflow.OrderCancelStatus, len(listResp.Orders))
+	batch.Run(len(listResp.Orders), cancelOrdersForUserConcurrency, func(i int) {
+		order := listResp.Orders[i]
+		statuses[i] = a.cancelOneUserOrder(ctx, order.OrderId, input.SagaID, input.Reason)
+	})
+
+	return &workflow.CancelOrdersForUserResult{Statuses: statuses}, nil
+}
+
+// cancelOneUserOrder cancels a single order found by CancelOrdersForUser,
+// reporting an already-settled/already-cancelled order as a non-fatal status
+// rather than an error.
+func (a *OrderBookActivities) cancelOneUserOrder(ctx context.Context, orderID, sagaID, reason string) *workflow.OrderCancelStatus {
+	logger := activity.GetLogger(ctx)
+
+	resp, err := a.orderBookClient.CancelBet(ctx, &orderbookpb.CancelBetRequest{
+		OrderId:        orderID,
+		SagaId:         sagaID,
+		IdempotencyKey: fmt.Sprintf("%s-cancel-%s", sagaID, orderID),
+	})
+	if err != nil {
+		return &workflow.OrderCancelStatus{OrderID: orderID, FailureReason: err.Error()}
+	}
+
+	if err := a.publisher.Publish(ctx, events.Event{
+		EventType: events.EventTypeSagaFailed,
+		SagaID:    sagaID,
+		OrderID:   orderID,
+		Reason:    reason,
+		Timestamp: time.Now(),
+	}); err != nil {
+		logger.Warn("Failed to publish SAGA_FAILED event for bulk cancel", "order_id", orderID, "error", err)
+	}
+
+	return &workflow.OrderCancelStatus{OrderID: orderID, Cancelled: resp.Status == "CANCELLED"}
+}
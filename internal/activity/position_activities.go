@@ -0,0 +1,185 @@
+package activity
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.temporal.io/sdk/activity"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	orderbookpb "github.com/cypherlabdev/cypherlabdev-protos/gen/go/orderbook/v1"
+	walletpb "github.com/cypherlabdev/cypherlabdev-protos/gen/go/wallet/v1"
+	"github.com/cypherlabdev/order-validator-service/internal/config"
+	"github.com/cypherlabdev/order-validator-service/internal/models"
+	"github.com/cypherlabdev/order-validator-service/internal/tracing"
+	"github.com/cypherlabdev/order-validator-service/internal/workflow"
+)
+
+// defaultRiskKey is the map key consulted when no currency/market-specific
+// override is configured
+const defaultRiskKey = "DEFAULT"
+
+// PositionActivities implements per-user exposure and risk checks that run
+// after basic validation and before funds are reserved
+type PositionActivities struct {
+	walletClient    walletpb.WalletServiceClient
+	orderBookClient orderbookpb.OrderBookServiceClient
+	logger          zerolog.Logger
+	limits          config.RiskConfig
+}
+
+// NewPositionActivities creates a new position activities instance
+func NewPositionActivities(walletServiceAddr, orderBookServiceAddr string, limits config.RiskConfig, logger zerolog.Logger) (*PositionActivities, error) {
+	walletConn, err := grpc.NewClient(
+		walletServiceAddr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+		grpc.WithChainUnaryInterceptor(tracing.UnaryClientInterceptor("wallet-service")),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to wallet-service: %w", err)
+	}
+
+	orderBookConn, err := grpc.NewClient(
+		orderBookServiceAddr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+		grpc.WithChainUnaryInterceptor(tracing.UnaryClientInterceptor("order-book-service")),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to order-book-service: %w", err)
+	}
+
+	return &PositionActivities{
+		walletClient:    walletpb.NewWalletServiceClient(walletConn),
+		orderBookClient: orderbookpb.NewOrderBookServiceClient(orderBookConn),
+		logger:          logger.With().Str("component", "position_activities").Logger(),
+		limits:          limits,
+	}, nil
+}
+
+// CheckExposure enforces per-currency/per-market exposure caps, a per-selection
+// concentration limit, and a daily-loss circuit breaker for the incoming order.
+// It aggregates the order's risk with the user's outstanding open risk on the
+// same (UserID, MarketID, SelectionID) tuple, pulled from wallet-service and
+// order-book-service.
+func (a *PositionActivities) CheckExposure(ctx context.Context, input workflow.CheckExposureInput) (*workflow.CheckExposureResult, error) {
+	logger := activity.GetLogger(ctx)
+	req := input.OrderRequest
+
+	incomingRisk := req.CalculateRisk()
+
+	openRisk, err := a.openRiskFor(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch open risk: %w", err)
+	}
+
+	totalRisk := openRisk.Add(incomingRisk)
+
+	maxExposure := decimalLimit(a.limits.MaxExposurePerCurrency, req.Currency)
+	if totalRisk.GreaterThan(maxExposure) {
+		reason := fmt.Sprintf("%s: total risk %s exceeds per-currency cap %s", models.ErrExposureExceeded, totalRisk, maxExposure)
+		logger.Warn("Order rejected", "reason", reason)
+		return &workflow.CheckExposureResult{Allowed: false, Reason: reason}, nil
+	}
+
+	maxMarketExposure := decimalLimit(a.limits.MaxExposurePerMarket, req.MarketID)
+	if totalRisk.GreaterThan(maxMarketExposure) {
+		reason := fmt.Sprintf("%s: total risk %s exceeds per-market cap %s", models.ErrExposureExceeded, totalRisk, maxMarketExposure)
+		logger.Warn("Order rejected", "reason", reason)
+		return &workflow.CheckExposureResult{Allowed: false, Reason: reason}, nil
+	}
+
+	bankroll, err := a.bankrollFor(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch bankroll: %w", err)
+	}
+
+	if bankroll.IsPositive() && a.limits.MaxConcentrationPct > 0 {
+		maxConcentration := decimal.NewFromFloat(a.limits.MaxConcentrationPct)
+		concentration := totalRisk.Div(bankroll)
+		if concentration.GreaterThan(maxConcentration) {
+			reason := fmt.Sprintf("%s: selection concentration %.2f%% exceeds cap %.2f%%",
+				models.ErrConcentrationExceeded, concentration.Mul(decimal.NewFromInt(100)).InexactFloat64(),
+				maxConcentration.Mul(decimal.NewFromInt(100)).InexactFloat64())
+			logger.Warn("Order rejected", "reason", reason)
+			return &workflow.CheckExposureResult{Allowed: false, Reason: reason}, nil
+		}
+	}
+
+	dailyPnL, err := a.dailyPnLFor(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch daily pnl: %w", err)
+	}
+
+	lossThreshold := decimalLimit(a.limits.DailyLossThreshold, req.Currency)
+	// An unbounded (missing) threshold comes back as decimalLimit's huge
+	// positive fallback; as a lower bound that is skipped rather than applied
+	if lossThreshold.LessThan(decimalLimit(nil, "")) && dailyPnL.LessThan(lossThreshold) {
+		reason := fmt.Sprintf("%s: daily pnl %s breached threshold %s", models.ErrDailyLossBreach, dailyPnL, lossThreshold)
+		logger.Warn("Order rejected", "reason", reason)
+		return &workflow.CheckExposureResult{Allowed: false, Reason: reason}, nil
+	}
+
+	return &workflow.CheckExposureResult{Allowed: true}, nil
+}
+
+// openRiskFor returns the user's outstanding open risk on the order's
+// (UserID, MarketID, SelectionID) tuple
+func (a *PositionActivities) openRiskFor(ctx context.Context, req *models.PlaceOrderRequest) (decimal.Decimal, error) {
+	resp, err := a.orderBookClient.GetOpenExposure(ctx, &orderbookpb.GetOpenExposureRequest{
+		UserId:      req.UserID.String(),
+		MarketId:    req.MarketID,
+		SelectionId: req.SelectionID,
+	})
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return decimal.NewFromString(resp.OpenRisk)
+}
+
+// bankrollFor returns the user's available balance in the order's currency
+func (a *PositionActivities) bankrollFor(ctx context.Context, req *models.PlaceOrderRequest) (decimal.Decimal, error) {
+	resp, err := a.walletClient.GetBalance(ctx, &walletpb.GetBalanceRequest{
+		UserId:   req.UserID.String(),
+		Currency: req.Currency,
+	})
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return decimal.NewFromString(resp.Balance)
+}
+
+// dailyPnLFor returns the user's realized P&L for the current day in the
+// order's currency
+func (a *PositionActivities) dailyPnLFor(ctx context.Context, req *models.PlaceOrderRequest) (decimal.Decimal, error) {
+	resp, err := a.walletClient.GetDailyPnL(ctx, &walletpb.GetDailyPnLRequest{
+		UserId:   req.UserID.String(),
+		Currency: req.Currency,
+	})
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return decimal.NewFromString(resp.RealizedPnl)
+}
+
+// decimalLimit looks up a currency/market-keyed limit, falling back to the
+// DEFAULT entry. An unparsable or missing limit is treated as unbounded.
+func decimalLimit(limits map[string]string, key string) decimal.Decimal {
+	raw, ok := limits[key]
+	if !ok {
+		raw, ok = limits[defaultRiskKey]
+	}
+	if !ok {
+		return decimal.NewFromInt(1<<62 - 1)
+	}
+	parsed, err := decimal.NewFromString(raw)
+	if err != nil {
+		return decimal.NewFromInt(1<<62 - 1)
+	}
+	return parsed
+}
@@ -0,0 +1,40 @@
+package activity
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cypherlabdev/order-validator-service/internal/config"
+)
+
+// TestPositionActivities_ImplementsInterface tests that PositionActivities implements the interface
+func TestPositionActivities_ImplementsInterface(t *testing.T) {
+	var _ PositionActivityInterface = (*PositionActivities)(nil)
+}
+
+// TestDecimalLimit_UsesSpecificOverride tests that a currency/market-specific limit wins
+func TestDecimalLimit_UsesSpecificOverride(t *testing.T) {
+	limits := map[string]string{
+		"DEFAULT": "5000",
+		"JPY":     "500000",
+	}
+
+	assert.Equal(t, decimal.NewFromInt(500000), decimalLimit(limits, "JPY"))
+	assert.Equal(t, decimal.NewFromInt(5000), decimalLimit(limits, "USD"))
+}
+
+// TestDecimalLimit_MissingDefaultIsUnbounded tests that a missing limit never rejects
+func TestDecimalLimit_MissingDefaultIsUnbounded(t *testing.T) {
+	limit := decimalLimit(map[string]string{}, "USD")
+	assert.True(t, limit.GreaterThan(decimal.NewFromInt(1_000_000_000)))
+}
+
+// TestRiskConfig_DefaultConcentrationIsFraction checks that 0.25, the default
+// concentration limit, is written as a fraction of bankroll (below 1.0) rather
+// than as a percentage
+func TestRiskConfig_DefaultConcentrationIsFraction(t *testing.T) {
+	cfg := config.RiskConfig{MaxConcentrationPct: 0.25}
+	assert.Less(t, cfg.MaxConcentrationPct, 1.0)
+}
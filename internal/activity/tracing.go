@@ -0,0 +1,7 @@
+package activity
+
+import "github.com/cypherlabdev/order-validator-service/internal/tracing"
+
+// tracer is shared by every activity in this package so their spans all
+// report under the same instrumentation scope
+var tracer = tracing.Tracer("github.com/cypherlabdev/order-validator-service/internal/activity")
@@ -3,11 +3,17 @@ package activity
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/rs/zerolog"
 	"github.com/shopspring/decimal"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"go.temporal.io/sdk/activity"
 
+	"github.com/cypherlabdev/order-validator-service/internal/events"
+	"github.com/cypherlabdev/order-validator-service/internal/models"
+	"github.com/cypherlabdev/order-validator-service/internal/tracing"
 	"github.com/cypherlabdev/order-validator-service/internal/workflow"
 )
 
@@ -17,34 +23,45 @@ type ValidationActivities struct {
 	// TODO: Add data-normalizer client when available
 	// dataNormalizerClient datanormalizerpb.DataNormalizerServiceClient
 
-	// Configuration
-	minStake decimal.Decimal
-	maxStake decimal.Decimal
-	minOdds  decimal.Decimal
-	maxOdds  decimal.Decimal
+	// rulesProvider supplies the currently effective stake/odds bounds,
+	// with per-currency/market/event overrides, and can be hot-swapped
+	rulesProvider RulesProvider
+
+	// maxOddsDeviationPct bounds how far submitted odds may drift from the
+	// mark-price-implied odds, expressed as a fraction (e.g. 0.1 = 10%)
+	maxOddsDeviationPct decimal.Decimal
+
+	// publisher announces ORDER_VALIDATED once an order passes validation
+	publisher events.Publisher
 }
 
 // NewValidationActivities creates a new validation activities instance
-func NewValidationActivities(logger zerolog.Logger) *ValidationActivities {
+func NewValidationActivities(rulesProvider RulesProvider, publisher events.Publisher, logger zerolog.Logger, maxOddsDeviationPct float64) *ValidationActivities {
 	return &ValidationActivities{
-		logger:   logger.With().Str("component", "validation_activities").Logger(),
-		minStake: decimal.NewFromFloat(1.0),   // $1 minimum
-		maxStake: decimal.NewFromFloat(10000.0), // $10,000 maximum
-		minOdds:  decimal.NewFromFloat(1.01),  // 1.01 minimum odds
-		maxOdds:  decimal.NewFromFloat(1000.0), // 1000.0 maximum odds
+		logger:              logger.With().Str("component", "validation_activities").Logger(),
+		rulesProvider:       rulesProvider,
+		maxOddsDeviationPct: decimal.NewFromFloat(maxOddsDeviationPct),
+		publisher:           publisher,
 	}
 }
 
 // ValidateOrderActivity validates an order request
 func (a *ValidationActivities) ValidateOrder(ctx context.Context, input workflow.ValidateOrderInput) (*workflow.ValidationResult, error) {
+	ctx, span := tracer.Start(ctx, "activity.ValidateOrder",
+		trace.WithAttributes(attribute.String("saga_id", input.SagaID)))
+	defer span.End()
+	ctx = tracing.WithSagaIDBaggage(ctx, input.SagaID)
+
 	logger := activity.GetLogger(ctx)
 	logger.Info("ValidateOrder activity started")
 
 	req := input.OrderRequest
+	rules := a.rulesProvider.Rules().Resolve(req.Currency, req.MarketID, req.EventID)
 
 	// 1. Basic validation (field validation)
 	if err := req.Validate(); err != nil {
 		logger.Warn("Order failed basic validation", "error", err)
+		span.AddEvent("validation.rejected", trace.WithAttributes(attribute.String("reason", err.Error())))
 		return &workflow.ValidationResult{
 			Valid:  false,
 			Reason: err.Error(),
@@ -52,18 +69,20 @@ func (a *ValidationActivities) ValidateOrder(ctx context.Context, input workflow
 	}
 
 	// 2. Stake limits
-	if req.Stake.LessThan(a.minStake) {
-		reason := fmt.Sprintf("stake too low: minimum is %s", a.minStake.String())
+	if req.Stake.LessThan(rules.MinStake) {
+		reason := fmt.Sprintf("stake too low: minimum is %s", rules.MinStake.String())
 		logger.Warn("Order rejected", "reason", reason)
+		span.AddEvent("validation.rejected", trace.WithAttributes(attribute.String("reason", reason)))
 		return &workflow.ValidationResult{
 			Valid:  false,
 			Reason: reason,
 		}, nil
 	}
 
-	if req.Stake.GreaterThan(a.maxStake) {
-		reason := fmt.Sprintf("stake too high: maximum is %s", a.maxStake.String())
+	if req.Stake.GreaterThan(rules.MaxStake) {
+		reason := fmt.Sprintf("stake too high: maximum is %s", rules.MaxStake.String())
 		logger.Warn("Order rejected", "reason", reason)
+		span.AddEvent("validation.rejected", trace.WithAttributes(attribute.String("reason", reason)))
 		return &workflow.ValidationResult{
 			Valid:  false,
 			Reason: reason,
@@ -71,24 +90,45 @@ func (a *ValidationActivities) ValidateOrder(ctx context.Context, input workflow
 	}
 
 	// 3. Odds limits
-	if req.Odds.LessThan(a.minOdds) {
-		reason := fmt.Sprintf("odds too low: minimum is %s", a.minOdds.String())
+	if req.Odds.LessThan(rules.MinOdds) {
+		reason := fmt.Sprintf("odds too low: minimum is %s", rules.MinOdds.String())
 		logger.Warn("Order rejected", "reason", reason)
+		span.AddEvent("validation.rejected", trace.WithAttributes(attribute.String("reason", reason)))
 		return &workflow.ValidationResult{
 			Valid:  false,
 			Reason: reason,
 		}, nil
 	}
 
-	if req.Odds.GreaterThan(a.maxOdds) {
-		reason := fmt.Sprintf("odds too high: maximum is %s", a.maxOdds.String())
+	if req.Odds.GreaterThan(rules.MaxOdds) {
+		reason := fmt.Sprintf("odds too high: maximum is %s", rules.MaxOdds.String())
 		logger.Warn("Order rejected", "reason", reason)
+		span.AddEvent("validation.rejected", trace.WithAttributes(attribute.String("reason", reason)))
 		return &workflow.ValidationResult{
 			Valid:  false,
 			Reason: reason,
 		}, nil
 	}
 
+	// 3b. Odds sanity band: reject odds that have drifted too far from the
+	// mark-price-implied odds. A zero MarkPrice means the caller skipped the
+	// market state lookup (e.g. unknown market), so the check is skipped.
+	if input.MarkPrice.IsPositive() {
+		deviation := req.Odds.Sub(input.MarkPrice).Abs().Div(input.MarkPrice)
+		if deviation.GreaterThan(a.maxOddsDeviationPct) {
+			reason := fmt.Sprintf("%s: odds deviate %.2f%% from mark price, exceeding band of %.2f%%",
+				models.ErrOddsOutOfRange,
+				deviation.Mul(decimal.NewFromInt(100)).InexactFloat64(),
+				a.maxOddsDeviationPct.Mul(decimal.NewFromInt(100)).InexactFloat64())
+			logger.Warn("Order rejected", "reason", reason)
+			span.AddEvent("validation.rejected", trace.WithAttributes(attribute.String("reason", reason)))
+			return &workflow.ValidationResult{
+				Valid:  false,
+				Reason: reason,
+			}, nil
+		}
+	}
+
 	// 4. Market validation (TODO: Call data-normalizer to check if market exists and is open)
 	// For now, we'll assume the market is valid
 	// In production, this would make a gRPC call to data-normalizer-service:
@@ -122,8 +162,39 @@ func (a *ValidationActivities) ValidateOrder(ctx context.Context, input workflow
 
 	logger.Info("Order validation passed")
 
+	if err := a.publisher.Publish(ctx, events.Event{
+		EventType: events.EventTypeOrderValidated,
+		SagaID:    input.SagaID,
+		UserID:    req.UserID.String(),
+		Timestamp: time.Now(),
+	}); err != nil {
+		logger.Warn("Failed to publish ORDER_VALIDATED event", "error", err)
+	}
+
 	return &workflow.ValidationResult{
 		Valid:  true,
 		Reason: "",
 	}, nil
 }
+
+// BatchValidateOrders validates a batch of order requests, reusing the same
+// rules as ValidateOrder for each entry. Results are keyed by IdempotencyKey
+// so the caller can match each outcome back to its input order.
+func (a *ValidationActivities) BatchValidateOrders(ctx context.Context, input workflow.BatchValidateOrdersInput) (*workflow.BatchValidationResult, error) {
+	logger := activity.GetLogger(ctx)
+	logger.Info("BatchValidateOrders activity started", "order_count", len(input.OrderRequests))
+
+	results := make(map[string]*workflow.ValidationResult, len(input.OrderRequests))
+	for _, req := range input.OrderRequests {
+		validationResult, err := a.ValidateOrder(ctx, workflow.ValidateOrderInput{
+			OrderRequest: req,
+			MarkPrice:    input.MarkPrices[req.IdempotencyKey],
+		})
+		if err != nil {
+			return nil, fmt.Errorf("batch validate order %s: %w", req.IdempotencyKey, err)
+		}
+		results[req.IdempotencyKey] = validationResult
+	}
+
+	return &workflow.BatchValidationResult{Results: results}, nil
+}
@@ -1,6 +1,7 @@
 package activity
 
 import (
+	"context"
 	"testing"
 
 	"github.com/google/uuid"
@@ -8,22 +9,43 @@ import (
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/cypherlabdev/order-validator-service/internal/events"
 	"github.com/cypherlabdev/order-validator-service/internal/models"
 	"github.com/cypherlabdev/order-validator-service/internal/workflow"
 )
 
+// testRulesProvider is a fixed-in-memory RulesProvider for seeding rules in tests
+type testRulesProvider struct {
+	rules *ValidationRulesConfig
+}
+
+func newTestRulesProvider(rules *ValidationRulesConfig) *testRulesProvider {
+	return &testRulesProvider{rules: rules}
+}
+
+func (p *testRulesProvider) Rules() *ValidationRulesConfig { return p.rules }
+
+func (p *testRulesProvider) SetRules(rules *ValidationRulesConfig) error {
+	p.rules = rules
+	return nil
+}
+
 // testValidationActivitySetup is a helper struct to hold test dependencies
 type testValidationActivitySetup struct {
 	activity *ValidationActivities
+	provider *testRulesProvider
 }
 
-// setupTestValidationActivity creates a test activity with all dependencies
+// setupTestValidationActivity creates a test activity seeded with the
+// default validation rules via the provider
 func setupTestValidationActivity(t *testing.T) *testValidationActivitySetup {
 	logger := zerolog.Nop()
-	activity := NewValidationActivities(logger)
+	provider := newTestRulesProvider(DefaultValidationRulesConfig())
+	activity := NewValidationActivities(provider, events.NewMemoryPublisher(), logger, 0.1)
 
 	return &testValidationActivitySetup{
 		activity: activity,
+		provider: provider,
 	}
 }
 
@@ -45,20 +67,9 @@ func TestValidateOrder_Success(t *testing.T) {
 		},
 	}
 
-	// Execute - test the validation logic directly
-	req := input.OrderRequest
-
-	// Test basic validation
-	err := req.Validate()
+	result, err := setup.activity.ValidateOrder(context.Background(), input)
 	assert.NoError(t, err)
-
-	// Test stake limits
-	assert.True(t, req.Stake.GreaterThanOrEqual(setup.activity.minStake))
-	assert.True(t, req.Stake.LessThanOrEqual(setup.activity.maxStake))
-
-	// Test odds limits
-	assert.True(t, req.Odds.GreaterThanOrEqual(setup.activity.minOdds))
-	assert.True(t, req.Odds.LessThanOrEqual(setup.activity.maxOdds))
+	assert.True(t, result.Valid)
 }
 
 // TestValidateOrder_BasicValidationFailure tests order with basic validation errors
@@ -130,16 +141,19 @@ func TestValidateOrder_BasicValidationFailure(t *testing.T) {
 		},
 	}
 
+	setup := setupTestValidationActivity(t)
+
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := tt.orderRequest.Validate()
-			assert.Error(t, err)
-			assert.Contains(t, err.Error(), tt.expectedError)
+			result, err := setup.activity.ValidateOrder(context.Background(), workflow.ValidateOrderInput{OrderRequest: tt.orderRequest})
+			assert.NoError(t, err)
+			assert.False(t, result.Valid)
+			assert.Contains(t, result.Reason, tt.expectedError)
 		})
 	}
 }
 
-// TestValidateOrder_StakeTooLow tests stake below minimum
+// TestValidateOrder_StakeTooLow tests stake below the default minimum
 func TestValidateOrder_StakeTooLow(t *testing.T) {
 	setup := setupTestValidationActivity(t)
 
@@ -150,20 +164,18 @@ func TestValidateOrder_StakeTooLow(t *testing.T) {
 		SelectionID:    "selection_789",
 		Side:           models.OrderSideBack,
 		Odds:           decimal.NewFromFloat(2.5),
-		Stake:          decimal.NewFromFloat(0.5), // Below minimum of 1.0
+		Stake:          decimal.NewFromFloat(0.5), // Below default minimum of 1.0
 		Currency:       "USD",
 		IdempotencyKey: "test_key",
 	}
 
-	// Basic validation should pass
-	err := req.Validate()
+	result, err := setup.activity.ValidateOrder(context.Background(), workflow.ValidateOrderInput{OrderRequest: req})
 	assert.NoError(t, err)
-
-	// But stake is below minimum
-	assert.True(t, req.Stake.LessThan(setup.activity.minStake))
+	assert.False(t, result.Valid)
+	assert.Contains(t, result.Reason, "stake too low")
 }
 
-// TestValidateOrder_StakeTooHigh tests stake above maximum
+// TestValidateOrder_StakeTooHigh tests stake above the default maximum
 func TestValidateOrder_StakeTooHigh(t *testing.T) {
 	setup := setupTestValidationActivity(t)
 
@@ -174,74 +186,41 @@ func TestValidateOrder_StakeTooHigh(t *testing.T) {
 		SelectionID:    "selection_789",
 		Side:           models.OrderSideBack,
 		Odds:           decimal.NewFromFloat(2.5),
-		Stake:          decimal.NewFromFloat(15000.0), // Above maximum of 10000.0
+		Stake:          decimal.NewFromFloat(15000.0), // Above default maximum of 10000.0
 		Currency:       "USD",
 		IdempotencyKey: "test_key",
 	}
 
-	// Basic validation should pass
-	err := req.Validate()
+	result, err := setup.activity.ValidateOrder(context.Background(), workflow.ValidateOrderInput{OrderRequest: req})
 	assert.NoError(t, err)
-
-	// But stake is above maximum
-	assert.True(t, req.Stake.GreaterThan(setup.activity.maxStake))
+	assert.False(t, result.Valid)
+	assert.Contains(t, result.Reason, "stake too high")
 }
 
-// TestValidateOrder_StakeAtBoundaries tests stake at exact boundaries
-func TestValidateOrder_StakeAtBoundaries(t *testing.T) {
+// TestValidateOrder_OddsTooLow tests odds below the default minimum
+func TestValidateOrder_OddsTooLow(t *testing.T) {
 	setup := setupTestValidationActivity(t)
 
-	tests := []struct {
-		name      string
-		stake     decimal.Decimal
-		withinMin bool
-		withinMax bool
-	}{
-		{
-			name:      "stake at minimum (1.0)",
-			stake:     decimal.NewFromFloat(1.0),
-			withinMin: true,
-			withinMax: true,
-		},
-		{
-			name:      "stake at maximum (10000.0)",
-			stake:     decimal.NewFromFloat(10000.0),
-			withinMin: true,
-			withinMax: true,
-		},
-		{
-			name:      "stake just below minimum",
-			stake:     decimal.NewFromFloat(0.99),
-			withinMin: false,
-			withinMax: true,
-		},
-		{
-			name:      "stake just above maximum",
-			stake:     decimal.NewFromFloat(10000.01),
-			withinMin: true,
-			withinMax: false,
-		},
+	req := &models.PlaceOrderRequest{
+		UserID:         uuid.New(),
+		EventID:        "event_123",
+		MarketID:       "market_456",
+		SelectionID:    "selection_789",
+		Side:           models.OrderSideBack,
+		Odds:           decimal.NewFromFloat(1.005), // Below default minimum of 1.01
+		Stake:          decimal.NewFromFloat(100.0),
+		Currency:       "USD",
+		IdempotencyKey: "test_key",
 	}
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			if tt.withinMin {
-				assert.True(t, tt.stake.GreaterThanOrEqual(setup.activity.minStake))
-			} else {
-				assert.True(t, tt.stake.LessThan(setup.activity.minStake))
-			}
-
-			if tt.withinMax {
-				assert.True(t, tt.stake.LessThanOrEqual(setup.activity.maxStake))
-			} else {
-				assert.True(t, tt.stake.GreaterThan(setup.activity.maxStake))
-			}
-		})
-	}
+	result, err := setup.activity.ValidateOrder(context.Background(), workflow.ValidateOrderInput{OrderRequest: req})
+	assert.NoError(t, err)
+	assert.False(t, result.Valid)
+	assert.Contains(t, result.Reason, "odds too low")
 }
 
-// TestValidateOrder_OddsTooLow tests odds below minimum
-func TestValidateOrder_OddsTooLow(t *testing.T) {
+// TestValidateOrder_OddsTooHigh tests odds above the default maximum
+func TestValidateOrder_OddsTooHigh(t *testing.T) {
 	setup := setupTestValidationActivity(t)
 
 	req := &models.PlaceOrderRequest{
@@ -250,23 +229,58 @@ func TestValidateOrder_OddsTooLow(t *testing.T) {
 		MarketID:       "market_456",
 		SelectionID:    "selection_789",
 		Side:           models.OrderSideBack,
-		Odds:           decimal.NewFromFloat(1.005), // Below minimum of 1.01
+		Odds:           decimal.NewFromFloat(1500.0), // Above default maximum of 1000.0
 		Stake:          decimal.NewFromFloat(100.0),
 		Currency:       "USD",
 		IdempotencyKey: "test_key",
 	}
 
-	// Basic validation should pass
-	err := req.Validate()
+	result, err := setup.activity.ValidateOrder(context.Background(), workflow.ValidateOrderInput{OrderRequest: req})
 	assert.NoError(t, err)
+	assert.False(t, result.Valid)
+	assert.Contains(t, result.Reason, "odds too high")
+}
 
-	// But odds are below minimum
-	assert.True(t, req.Odds.LessThan(setup.activity.minOdds))
+// TestValidateOrder_CurrencyOverrideShadowsDefault tests that a currency
+// override's stake bounds take precedence over the global default
+func TestValidateOrder_CurrencyOverrideShadowsDefault(t *testing.T) {
+	rules := DefaultValidationRulesConfig()
+	rules.CurrencyOverrides = map[string]ValidationRules{
+		"JPY": {MinStake: decimal.NewFromInt(100), MaxStake: decimal.NewFromInt(1000000)},
+	}
+	provider := newTestRulesProvider(rules)
+	a := NewValidationActivities(provider, events.NewMemoryPublisher(), zerolog.Nop(), 0.1)
+
+	req := &models.PlaceOrderRequest{
+		UserID:         uuid.New(),
+		EventID:        "event_123",
+		MarketID:       "market_456",
+		SelectionID:    "selection_789",
+		Side:           models.OrderSideBack,
+		Odds:           decimal.NewFromFloat(2.5),
+		Stake:          decimal.NewFromFloat(50), // above global default min (1.0), but below JPY override min (100)
+		Currency:       "JPY",
+		IdempotencyKey: "test_key",
+	}
+
+	result, err := a.ValidateOrder(context.Background(), workflow.ValidateOrderInput{OrderRequest: req})
+	assert.NoError(t, err)
+	assert.False(t, result.Valid)
+	assert.Contains(t, result.Reason, "stake too low")
 }
 
-// TestValidateOrder_OddsTooHigh tests odds above maximum
-func TestValidateOrder_OddsTooHigh(t *testing.T) {
-	setup := setupTestValidationActivity(t)
+// TestValidateOrder_MarketOverrideShadowsCurrencyOverride tests that a market
+// override takes precedence over a currency override for the same field
+func TestValidateOrder_MarketOverrideShadowsCurrencyOverride(t *testing.T) {
+	rules := DefaultValidationRulesConfig()
+	rules.CurrencyOverrides = map[string]ValidationRules{
+		"USD": {MaxStake: decimal.NewFromInt(10000)},
+	}
+	rules.MarketOverrides = map[string]ValidationRules{
+		"market_456": {MaxStake: decimal.NewFromInt(100000)}, // high-liquidity market allows bigger stakes
+	}
+	provider := newTestRulesProvider(rules)
+	a := NewValidationActivities(provider, events.NewMemoryPublisher(), zerolog.Nop(), 0.1)
 
 	req := &models.PlaceOrderRequest{
 		UserID:         uuid.New(),
@@ -274,71 +288,72 @@ func TestValidateOrder_OddsTooHigh(t *testing.T) {
 		MarketID:       "market_456",
 		SelectionID:    "selection_789",
 		Side:           models.OrderSideBack,
-		Odds:           decimal.NewFromFloat(1500.0), // Above maximum of 1000.0
-		Stake:          decimal.NewFromFloat(100.0),
+		Odds:           decimal.NewFromFloat(2.5),
+		Stake:          decimal.NewFromInt(50000), // above USD override's max, within market override's max
 		Currency:       "USD",
 		IdempotencyKey: "test_key",
 	}
 
-	// Basic validation should pass
-	err := req.Validate()
+	result, err := a.ValidateOrder(context.Background(), workflow.ValidateOrderInput{OrderRequest: req})
 	assert.NoError(t, err)
+	assert.True(t, result.Valid)
+}
 
-	// But odds are above maximum
-	assert.True(t, req.Odds.GreaterThan(setup.activity.maxOdds))
+// TestValidationRulesConfig_Resolve_FallsBackToDefault tests that an
+// unconfigured currency/market/event falls back to the global default
+func TestValidationRulesConfig_Resolve_FallsBackToDefault(t *testing.T) {
+	rules := DefaultValidationRulesConfig()
+	resolved := rules.Resolve("EUR", "market_unknown", "event_unknown")
+	assert.Equal(t, rules.Default, resolved)
 }
 
-// TestValidateOrder_OddsAtBoundaries tests odds at exact boundaries
-func TestValidateOrder_OddsAtBoundaries(t *testing.T) {
+// TestValidateOrder_OddsWithinSanityBand tests odds close to the mark price pass
+func TestValidateOrder_OddsWithinSanityBand(t *testing.T) {
 	setup := setupTestValidationActivity(t)
 
-	tests := []struct {
-		name      string
-		odds      decimal.Decimal
-		withinMin bool
-		withinMax bool
-	}{
-		{
-			name:      "odds at minimum (1.01)",
-			odds:      decimal.NewFromFloat(1.01),
-			withinMin: true,
-			withinMax: true,
-		},
-		{
-			name:      "odds at maximum (1000.0)",
-			odds:      decimal.NewFromFloat(1000.0),
-			withinMin: true,
-			withinMax: true,
-		},
-		{
-			name:      "odds just below minimum",
-			odds:      decimal.NewFromFloat(1.009),
-			withinMin: false,
-			withinMax: true,
-		},
-		{
-			name:      "odds just above maximum",
-			odds:      decimal.NewFromFloat(1000.01),
-			withinMin: true,
-			withinMax: false,
-		},
+	req := &models.PlaceOrderRequest{
+		UserID:         uuid.New(),
+		EventID:        "event_123",
+		MarketID:       "market_456",
+		SelectionID:    "selection_789",
+		Side:           models.OrderSideBack,
+		Odds:           decimal.NewFromFloat(2.5),
+		Stake:          decimal.NewFromFloat(100.0),
+		Currency:       "USD",
+		IdempotencyKey: "test_key",
 	}
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			if tt.withinMin {
-				assert.True(t, tt.odds.GreaterThanOrEqual(setup.activity.minOdds))
-			} else {
-				assert.True(t, tt.odds.LessThan(setup.activity.minOdds))
-			}
+	result, err := setup.activity.ValidateOrder(context.Background(), workflow.ValidateOrderInput{
+		OrderRequest: req,
+		MarkPrice:    decimal.NewFromFloat(2.55),
+	})
+	assert.NoError(t, err)
+	assert.True(t, result.Valid)
+}
 
-			if tt.withinMax {
-				assert.True(t, tt.odds.LessThanOrEqual(setup.activity.maxOdds))
-			} else {
-				assert.True(t, tt.odds.GreaterThan(setup.activity.maxOdds))
-			}
-		})
+// TestValidateOrder_OddsOutsideSanityBand tests odds too far from the mark price fail
+func TestValidateOrder_OddsOutsideSanityBand(t *testing.T) {
+	setup := setupTestValidationActivity(t)
+
+	req := &models.PlaceOrderRequest{
+		UserID:         uuid.New(),
+		EventID:        "event_123",
+		MarketID:       "market_456",
+		SelectionID:    "selection_789",
+		Side:           models.OrderSideBack,
+		Odds:           decimal.NewFromFloat(5.0),
+		Stake:          decimal.NewFromFloat(100.0),
+		Currency:       "USD",
+		IdempotencyKey: "test_key",
 	}
+
+	result, err := setup.activity.ValidateOrder(context.Background(), workflow.ValidateOrderInput{
+		OrderRequest: req,
+		MarkPrice:    decimal.NewFromFloat(2.5),
+	})
+	assert.NoError(t, err)
+	assert.False(t, result.Valid)
+	assert.Contains(t, result.Reason, models.ErrOddsOutOfRange)
 }
 
 // TestValidateOrder_BackSide tests validation with BACK side
@@ -404,16 +419,19 @@ func TestValidateOrder_DifferentCurrencies(t *testing.T) {
 // TestNewValidationActivities tests activity creation
 func TestNewValidationActivities(t *testing.T) {
 	logger := zerolog.Nop()
-	activity := NewValidationActivities(logger)
+	provider := newTestRulesProvider(DefaultValidationRulesConfig())
+	a := NewValidationActivities(provider, events.NewMemoryPublisher(), logger, 0.1)
 
-	assert.NotNil(t, activity)
-	assert.Equal(t, decimal.NewFromFloat(1.0), activity.minStake)
-	assert.Equal(t, decimal.NewFromFloat(10000.0), activity.maxStake)
-	assert.Equal(t, decimal.NewFromFloat(1.01), activity.minOdds)
-	assert.Equal(t, decimal.NewFromFloat(1000.0), activity.maxOdds)
+	assert.NotNil(t, a)
+	assert.Equal(t, provider, a.rulesProvider)
 }
 
 // TestValidationActivities_ImplementsInterface tests that ValidationActivities implements the interface
 func TestValidationActivities_ImplementsInterface(t *testing.T) {
 	var _ ValidationActivityInterface = (*ValidationActivities)(nil)
 }
+
+// TestRulesProvider_ImplementedByTestProvider tests that testRulesProvider implements RulesProvider
+func TestRulesProvider_ImplementedByTestProvider(t *testing.T) {
+	var _ RulesProvider = (*testRulesProvider)(nil)
+}
@@ -0,0 +1,143 @@
+package activity
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/shopspring/decimal"
+)
+
+// ValidationRules is a set of stake/odds bounds applied to an order
+type ValidationRules struct {
+	MinStake decimal.Decimal `json:"min_stake"`
+	MaxStake decimal.Decimal `json:"max_stake"`
+	MinOdds  decimal.Decimal `json:"min_odds"`
+	MaxOdds  decimal.Decimal `json:"max_odds"`
+}
+
+// ValidationRulesConfig holds global default validation rules plus overrides
+// keyed by currency, market ID, and event ID. An override only needs to set
+// the fields it wants to change - zero fields fall back to the default.
+type ValidationRulesConfig struct {
+	Default           ValidationRules            `json:"default"`
+	CurrencyOverrides map[string]ValidationRules `json:"currency_overrides"`
+	MarketOverrides   map[string]ValidationRules `json:"market_overrides"`
+	EventOverrides    map[string]ValidationRules `json:"event_overrides"`
+}
+
+// DefaultValidationRulesConfig returns the rules this service shipped with
+// before rules became configurable
+func DefaultValidationRulesConfig() *ValidationRulesConfig {
+	return &ValidationRulesConfig{
+		Default: ValidationRules{
+			MinStake: decimal.NewFromFloat(1.0),
+			MaxStake: decimal.NewFromFloat(10000.0),
+			MinOdds:  decimal.NewFromFloat(1.01),
+			MaxOdds:  decimal.NewFromFloat(1000.0),
+		},
+	}
+}
+
+// Resolve computes the effective rules for an order, applying currency,
+// market, and event overrides on top of the defaults, in that order, so a
+// market override shadows a currency override and an event override shadows
+// both.
+func (c *ValidationRulesConfig) Resolve(currency, marketID, eventID string) ValidationRules {
+	rules := c.Default
+
+	if override, ok := c.CurrencyOverrides[currency]; ok {
+		rules = mergeValidationRules(rules, override)
+	}
+	if override, ok := c.MarketOverrides[marketID]; ok {
+		rules = mergeValidationRules(rules, override)
+	}
+	if override, ok := c.EventOverrides[eventID]; ok {
+		rules = mergeValidationRules(rules, override)
+	}
+
+	return rules
+}
+
+// mergeValidationRules returns base with any non-zero field in override applied
+func mergeValidationRules(base, override ValidationRules) ValidationRules {
+	if !override.MinStake.IsZero() {
+		base.MinStake = override.MinStake
+	}
+	if !override.MaxStake.IsZero() {
+		base.MaxStake = override.MaxStake
+	}
+	if !override.MinOdds.IsZero() {
+		base.MinOdds = override.MinOdds
+	}
+	if !override.MaxOdds.IsZero() {
+		base.MaxOdds = override.MaxOdds
+	}
+	return base
+}
+
+// RulesProvider supplies the currently effective validation rules and allows
+// them to be swapped at runtime without restarting the service
+type RulesProvider interface {
+	Rules() *ValidationRulesConfig
+	SetRules(rules *ValidationRulesConfig) error
+}
+
+// FileRulesProvider is a RulesProvider backed by a JSON file on disk. Rules
+// are loaded once at startup and held in memory thereafter; SetRules swaps
+// the in-memory copy atomically and persists it back to the file so the new
+// rules survive a restart.
+type FileRulesProvider struct {
+	mu    sync.RWMutex
+	rules *ValidationRulesConfig
+	path  string
+}
+
+// NewFileRulesProvider creates a FileRulesProvider, loading rules from path
+// if it exists, falling back to defaults otherwise
+func NewFileRulesProvider(path string, defaults *ValidationRulesConfig) (*FileRulesProvider, error) {
+	p := &FileRulesProvider{path: path, rules: defaults}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return p, nil
+		}
+		return nil, fmt.Errorf("read rules file: %w", err)
+	}
+
+	var rules ValidationRulesConfig
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("parse rules file: %w", err)
+	}
+	p.rules = &rules
+
+	return p, nil
+}
+
+// Rules returns the currently effective rules
+func (p *FileRulesProvider) Rules() *ValidationRulesConfig {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.rules
+}
+
+// SetRules atomically swaps the effective rules and persists them to disk
+func (p *FileRulesProvider) SetRules(rules *ValidationRulesConfig) error {
+	if p.path != "" {
+		data, err := json.MarshalIndent(rules, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshal rules: %w", err)
+		}
+		if err := os.WriteFile(p.path, data, 0o644); err != nil {
+			return fmt.Errorf("write rules file: %w", err)
+		}
+	}
+
+	p.mu.Lock()
+	p.rules = rules
+	p.mu.Unlock()
+
+	return nil
+}
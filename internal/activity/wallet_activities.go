@@ -3,29 +3,40 @@ package activity
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/rs/zerolog"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	"go.temporal.io/sdk/activity"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 
 	walletpb "github.com/cypherlabdev/cypherlabdev-protos/gen/go/wallet/v1"
+	"github.com/cypherlabdev/order-validator-service/internal/batch"
+	"github.com/cypherlabdev/order-validator-service/internal/events"
+	"github.com/cypherlabdev/order-validator-service/internal/tracing"
 	"github.com/cypherlabdev/order-validator-service/internal/workflow"
 )
 
 // WalletActivities implements wallet-related activities
 type WalletActivities struct {
 	walletClient walletpb.WalletServiceClient
+	publisher    events.Publisher
 	logger       zerolog.Logger
 }
 
 // NewWalletActivities creates a new wallet activities instance
-func NewWalletActivities(walletServiceAddr string, logger zerolog.Logger) (*WalletActivities, error) {
+func NewWalletActivities(walletServiceAddr string, publisher events.Publisher, logger zerolog.Logger) (*WalletActivities, error) {
 	// Connect to wallet-service
 	conn, err := grpc.NewClient(
 		walletServiceAddr,
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+		grpc.WithChainUnaryInterceptor(tracing.UnaryClientInterceptor("wallet-service")),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to wallet-service: %w", err)
@@ -35,12 +46,18 @@ func NewWalletActivities(walletServiceAddr string, logger zerolog.Logger) (*Wall
 
 	return &WalletActivities{
 		walletClient: client,
+		publisher:    publisher,
 		logger:       logger.With().Str("component", "wallet_activities").Logger(),
 	}, nil
 }
 
 // ReserveFundsActivity reserves funds in the wallet
 func (a *WalletActivities) ReserveFunds(ctx context.Context, input workflow.ReserveFundsInput) (*workflow.ReserveFundsResult, error) {
+	ctx, span := tracer.Start(ctx, "activity.ReserveFunds",
+		trace.WithAttributes(attribute.String("saga_id", input.SagaID)))
+	defer span.End()
+	ctx = tracing.WithSagaIDBaggage(ctx, input.SagaID)
+
 	logger := activity.GetLogger(ctx)
 	logger.Info("ReserveFunds activity started", "user_id", input.UserID, "amount", input.Amount)
 
@@ -54,11 +71,25 @@ func (a *WalletActivities) ReserveFunds(ctx context.Context, input workflow.Rese
 	resp, err := a.walletClient.ReserveBalance(ctx, req)
 	if err != nil {
 		logger.Error("Failed to reserve funds", "error", err)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
 		return nil, fmt.Errorf("reserve funds: %w", err)
 	}
 
+	span.SetAttributes(attribute.String("reservation_id", resp.ReservationId))
+	span.AddEvent("wallet.reservation_created", trace.WithAttributes(attribute.String("reservation_id", resp.ReservationId)))
 	logger.Info("Funds reserved successfully", "reservation_id", resp.ReservationId)
 
+	if err := a.publisher.Publish(ctx, events.Event{
+		EventType:     events.EventTypeFundsReserved,
+		SagaID:        input.SagaID,
+		UserID:        input.UserID,
+		ReservationID: resp.ReservationId,
+		Timestamp:     time.Now(),
+	}); err != nil {
+		logger.Warn("Failed to publish FUNDS_RESERVED event", "error", err)
+	}
+
 	return &workflow.ReserveFundsResult{
 		ReservationID: resp.ReservationId,
 		Status:        resp.Status,
@@ -67,6 +98,14 @@ func (a *WalletActivities) ReserveFunds(ctx context.Context, input workflow.Rese
 
 // CommitReservationActivity commits a reservation
 func (a *WalletActivities) CommitReservation(ctx context.Context, input workflow.CommitReservationInput) (*workflow.CommitReservationResult, error) {
+	ctx, span := tracer.Start(ctx, "activity.CommitReservation",
+		trace.WithAttributes(
+			attribute.String("saga_id", input.SagaID),
+			attribute.String("reservation_id", input.ReservationID),
+		))
+	defer span.End()
+	ctx = tracing.WithSagaIDBaggage(ctx, input.SagaID)
+
 	logger := activity.GetLogger(ctx)
 	logger.Info("CommitReservation activity started", "reservation_id", input.ReservationID)
 
@@ -79,11 +118,22 @@ func (a *WalletActivities) CommitReservation(ctx context.Context, input workflow
 	resp, err := a.walletClient.CommitReservation(ctx, req)
 	if err != nil {
 		logger.Error("Failed to commit reservation", "error", err)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
 		return nil, fmt.Errorf("commit reservation: %w", err)
 	}
 
 	logger.Info("Reservation committed successfully")
 
+	if err := a.publisher.Publish(ctx, events.Event{
+		EventType:     events.EventTypeOrderMatched,
+		SagaID:        input.SagaID,
+		ReservationID: input.ReservationID,
+		Timestamp:     time.Now(),
+	}); err != nil {
+		logger.Warn("Failed to publish ORDER_MATCHED event", "error", err)
+	}
+
 	return &workflow.CommitReservationResult{
 		Status: resp.Status,
 	}, nil
@@ -91,6 +141,15 @@ func (a *WalletActivities) CommitReservation(ctx context.Context, input workflow
 
 // CancelReservationActivity cancels a reservation
 func (a *WalletActivities) CancelReservation(ctx context.Context, input workflow.CancelReservationInput) (*workflow.CancelReservationResult, error) {
+	ctx, span := tracer.Start(ctx, "activity.CancelReservation",
+		trace.WithAttributes(
+			attribute.String("saga_id", input.SagaID),
+			attribute.String("reservation_id", input.ReservationID),
+		))
+	defer span.End()
+	ctx = tracing.WithSagaIDBaggage(ctx, input.SagaID)
+	span.AddEvent("saga.compensation_started", trace.WithAttributes(attribute.String("reservation_id", input.ReservationID)))
+
 	logger := activity.GetLogger(ctx)
 	logger.Info("CancelReservation activity started", "reservation_id", input.ReservationID)
 
@@ -103,12 +162,79 @@ func (a *WalletActivities) CancelReservation(ctx context.Context, input workflow
 	resp, err := a.walletClient.CancelReservation(ctx, req)
 	if err != nil {
 		logger.Error("Failed to cancel reservation", "error", err)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
 		return nil, fmt.Errorf("cancel reservation: %w", err)
 	}
 
 	logger.Info("Reservation cancelled successfully")
 
+	if err := a.publisher.Publish(ctx, events.Event{
+		EventType:     events.EventTypeOrderCancelled,
+		SagaID:        input.SagaID,
+		ReservationID: input.ReservationID,
+		Timestamp:     time.Now(),
+	}); err != nil {
+		logger.Warn("Failed to publish ORDER_CANCELLED event", "error", err)
+	}
+
 	return &workflow.CancelReservationResult{
 		Status: resp.Status,
 	}, nil
 }
+
+// cancelReservationsForUserConcurrency bounds how many CancelReservation
+// calls CancelReservationsForUser makes at once
+const cancelReservationsForUserConcurrency = 10
+
+// CancelReservationsForUser lists every reservation the wallet still holds
+// for the user and releases each one concurrently. A reservation that has
+// already been committed or cancelled is reported as such rather than
+// failing the whole activity, so CancelUserOrdersWorkflow can report a true
+// partial-success status list.
+func (a *WalletActivities) CancelReservationsForUser(ctx context.Context, input workflow.CancelReservationsForUserInput) (*workflow.CancelReservationsForUserResult, error) {
+	ctx, span := tracer.Start(ctx, "activity.CancelReservationsForUser",
+		trace.WithAttributes(attribute.String("saga_id", input.SagaID), attribute.String("user_id", input.UserID)))
+	defer span.End()
+	ctx = tracing.WithSagaIDBaggage(ctx, input.SagaID)
+	span.AddEvent("saga.compensation_started", trace.WithAttributes(attribute.String("user_id", input.UserID)))
+
+	logger := activity.GetLogger(ctx)
+	logger.Info("CancelReservationsForUser activity started", "user_id", input.UserID, "currency", input.Currency, "market_id", input.MarketID)
+
+	listResp, err := a.walletClient.ListOpenReservations(ctx, &walletpb.ListOpenReservationsRequest{
+		UserId:   input.UserID,
+		Currency: input.Currency,
+		MarketId: input.MarketID,
+	})
+	if err != nil {
+		logger.Error("Failed to list open reservations for user", "error", err)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, fmt.Errorf("list open reservations for user: %w", err)
+	}
+
+	statuses := make([]*workflow.ReservationCancelStatus, len(listResp.Reservations))
+	batch.Run(len(listResp.Reservations), cancelReservationsForUserConcurrency, func(i int) {
+		reservationID := listResp.Reservations[i].ReservationId
+		statuses[i] = a.cancelOneUserReservation(ctx, reservationID, input.SagaID)
+	})
+
+	return &workflow.CancelReservationsForUserResult{Statuses: statuses}, nil
+}
+
+// cancelOneUserReservation cancels a single reservation found by
+// CancelReservationsForUser, reporting an already-committed/already-cancelled
+// reservation as a non-fatal status rather than an error.
+func (a *WalletActivities) cancelOneUserReservation(ctx context.Context, reservationID, sagaID string) *workflow.ReservationCancelStatus {
+	resp, err := a.walletClient.CancelReservation(ctx, &walletpb.CancelReservationRequest{
+		ReservationId:  reservationID,
+		SagaId:         sagaID,
+		IdempotencyKey: fmt.Sprintf("%s-cancel-%s", sagaID, reservationID),
+	})
+	if err != nil {
+		return &workflow.ReservationCancelStatus{ReservationID: reservationID, FailureReason: err.Error()}
+	}
+
+	return &workflow.ReservationCancelStatus{ReservationID: reservationID, Cancelled: resp.Status == "CANCELLED"}
+}
@@ -0,0 +1,30 @@
+package batch
+
+import "sync"
+
+// Run calls fn(i) for every i in [0, n), running at most concurrency calls
+// at once, and blocks until every call has returned. A concurrency <= 0 or
+// >= n runs all n calls at once.
+func Run(n, concurrency int, fn func(i int)) {
+	if n == 0 {
+		return
+	}
+	if concurrency <= 0 || concurrency > n {
+		concurrency = n
+	}
+
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	wg.Add(n)
+
+	for i := 0; i < n; i++ {
+		sem <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			fn(i)
+		}(i)
+	}
+
+	wg.Wait()
+}
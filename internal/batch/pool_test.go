@@ -0,0 +1,46 @@
+package batch
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRun_CallsEveryIndexExactlyOnce(t *testing.T) {
+	const n = 50
+	seen := make([]int32, n)
+
+	Run(n, 8, func(i int) {
+		atomic.AddInt32(&seen[i], 1)
+	})
+
+	for i, count := range seen {
+		assert.Equal(t, int32(1), count, "index %d", i)
+	}
+}
+
+func TestRun_BoundsConcurrency(t *testing.T) {
+	const n = 100
+	const concurrency = 5
+
+	var current, max int32
+	Run(n, concurrency, func(i int) {
+		c := atomic.AddInt32(&current, 1)
+		for {
+			m := atomic.LoadInt32(&max)
+			if c <= m || atomic.CompareAndSwapInt32(&max, m, c) {
+				break
+			}
+		}
+		atomic.AddInt32(&current, -1)
+	})
+
+	assert.LessOrEqual(t, max, int32(concurrency))
+}
+
+func TestRun_ZeroItemsReturnsImmediately(t *testing.T) {
+	called := false
+	Run(0, 4, func(i int) { called = true })
+	assert.False(t, called)
+}
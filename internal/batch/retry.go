@@ -0,0 +1,55 @@
+package batch
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+// RetryPolicy describes a fixed backoff schedule: attempt N (1-indexed) waits
+// for Backoffs[N-1] plus up to Jitter before retrying. The policy is
+// exhausted once len(Backoffs) retries have been attempted.
+type RetryPolicy struct {
+	Backoffs []time.Duration
+	Jitter   time.Duration
+}
+
+// DefaultRetryPolicy is the 100ms/500ms/2s backoff used for per-item retries
+// across batch operations (e.g. PlaceBets workflow starts)
+var DefaultRetryPolicy = RetryPolicy{
+	Backoffs: []time.Duration{100 * time.Millisecond, 500 * time.Millisecond, 2 * time.Second},
+	Jitter:   50 * time.Millisecond,
+}
+
+// IsRetryable decides whether an error returned from an attempt should be retried
+type IsRetryable func(err error) bool
+
+// Retry calls fn until it succeeds, fn's error is not retryable according to
+// retryable, or policy's backoff schedule is exhausted. It returns the error
+// from the final attempt (nil on success).
+func Retry(ctx context.Context, policy RetryPolicy, retryable IsRetryable, fn func() error) error {
+	var err error
+	for attempt := 0; ; attempt++ {
+		err = fn()
+		if err == nil {
+			return nil
+		}
+		if !retryable(err) {
+			return err
+		}
+		if attempt >= len(policy.Backoffs) {
+			return err
+		}
+
+		wait := policy.Backoffs[attempt]
+		if policy.Jitter > 0 {
+			wait += time.Duration(rand.Int63n(int64(policy.Jitter)))
+		}
+
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package batch
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func alwaysRetryable(err error) bool { return true }
+
+func TestRetry_SucceedsWithoutRetrying(t *testing.T) {
+	calls := 0
+	err := Retry(context.Background(), DefaultRetryPolicy, alwaysRetryable, func() error {
+		calls++
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestRetry_RetriesUntilSuccess(t *testing.T) {
+	policy := RetryPolicy{Backoffs: []time.Duration{time.Millisecond, time.Millisecond}}
+	calls := 0
+	err := Retry(context.Background(), policy, alwaysRetryable, func() error {
+		calls++
+		if calls < 3 {
+			return errors.New("transient")
+		}
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, calls)
+}
+
+func TestRetry_StopsImmediatelyOnNonRetryableError(t *testing.T) {
+	wantErr := errors.New("fatal")
+	calls := 0
+	err := Retry(context.Background(), DefaultRetryPolicy, func(error) bool { return false }, func() error {
+		calls++
+		return wantErr
+	})
+
+	assert.Equal(t, wantErr, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestRetry_ReturnsLastErrorOnceBackoffExhausted(t *testing.T) {
+	policy := RetryPolicy{Backoffs: []time.Duration{time.Millisecond}}
+	calls := 0
+	err := Retry(context.Background(), policy, alwaysRetryable, func() error {
+		calls++
+		return errors.New("still failing")
+	})
+
+	assert.Error(t, err)
+	assert.Equal(t, 2, calls) // initial attempt + 1 retry
+}
+
+func TestRetry_StopsOnContextCancellation(t *testing.T) {
+	policy := RetryPolicy{Backoffs: []time.Duration{time.Hour}}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := Retry(ctx, policy, alwaysRetryable, func() error {
+		return errors.New("transient")
+	})
+
+	assert.ErrorIs(t, err, context.Canceled)
+}
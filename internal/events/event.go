@@ -0,0 +1,61 @@
+package events
+
+import "time"
+
+// EventType enumerates the saga/order lifecycle milestones published onto the event bus
+type EventType string
+
+const (
+	EventTypeOrderValidated EventType = "ORDER_VALIDATED"
+	EventTypeFundsReserved  EventType = "FUNDS_RESERVED"
+	EventTypeOrderPlaced    EventType = "ORDER_PLACED"
+	EventTypeOrderMatched   EventType = "ORDER_MATCHED"
+	EventTypeOrderCancelled EventType = "ORDER_CANCELLED"
+	EventTypeSagaFailed     EventType = "SAGA_FAILED"
+
+	// EventTypeOrderFilled uses the lowercase "order.fill" value (rather than
+	// the SAGA_FAILED-style convention above) to match the settlement event
+	// name already in use by downstream fill/ledger consumers.
+	EventTypeOrderFilled EventType = "order.fill"
+)
+
+// schemaVersion is bumped whenever Event's shape changes in a way that isn't
+// backward compatible, so long-lived subscribers can detect and handle skew
+const schemaVersion = 1
+
+// Event is the versioned payload published for every saga lifecycle
+// milestone. Only the fields relevant to EventType are populated.
+type Event struct {
+	Version   int       `json:"version"`
+	EventType EventType `json:"event_type"`
+	SagaID    string    `json:"saga_id"`
+	UserID    string    `json:"user_id"`
+	OrderID   string    `json:"order_id,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+
+	ReservationID string `json:"reservation_id,omitempty"` // FUNDS_RESERVED
+	MatchID       string `json:"match_id,omitempty"`       // ORDER_PLACED, order.fill
+	Reason        string `json:"reason,omitempty"`         // SAGA_FAILED
+	MatchedOdds   string `json:"matched_odds,omitempty"`   // order.fill
+	MatchedStake  string `json:"matched_stake,omitempty"`  // order.fill
+}
+
+// UserChannel is the pub/sub channel carrying every event for a given user
+func UserChannel(userID string) string {
+	return "user:" + userID
+}
+
+// SagaChannel is the pub/sub channel carrying every event for a given saga
+func SagaChannel(sagaID string) string {
+	return "saga:" + sagaID
+}
+
+// broadcastChannel carries every event regardless of user or saga, for
+// consumers (such as the WebSocket bridge) that don't know their channels of
+// interest ahead of time
+const broadcastChannel = "broadcast:all"
+
+// BroadcastChannel is the pub/sub channel carrying every published event
+func BroadcastChannel() string {
+	return broadcastChannel
+}
@@ -0,0 +1,67 @@
+package events
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryPublisher is an in-process Publisher/Subscriber, suitable for tests
+// and single-process deployments with no Redis dependency
+type MemoryPublisher struct {
+	mu          sync.RWMutex
+	subscribers map[string][]chan Event
+}
+
+// NewMemoryPublisher creates a new in-memory publisher
+func NewMemoryPublisher() *MemoryPublisher {
+	return &MemoryPublisher{subscribers: make(map[string][]chan Event)}
+}
+
+// Publish implements Publisher
+func (p *MemoryPublisher) Publish(ctx context.Context, event Event) error {
+	event.Version = schemaVersion
+	p.broadcast(UserChannel(event.UserID), event)
+	p.broadcast(SagaChannel(event.SagaID), event)
+	p.broadcast(BroadcastChannel(), event)
+	return nil
+}
+
+func (p *MemoryPublisher) broadcast(channel string, event Event) {
+	p.mu.RLock()
+	subs := append([]chan Event(nil), p.subscribers[channel]...)
+	p.mu.RUnlock()
+
+	for _, ch := range subs {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+// Subscribe implements Subscriber
+func (p *MemoryPublisher) Subscribe(ctx context.Context, channel string) <-chan Event {
+	ch := make(chan Event, 16)
+
+	p.mu.Lock()
+	p.subscribers[channel] = append(p.subscribers[channel], ch)
+	p.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+
+		p.mu.Lock()
+		defer p.mu.Unlock()
+
+		subs := p.subscribers[channel]
+		for i, c := range subs {
+			if c == ch {
+				p.subscribers[channel] = append(subs[:i], subs[i+1:]...)
+				break
+			}
+		}
+		close(ch)
+	}()
+
+	return ch
+}
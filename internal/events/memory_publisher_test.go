@@ -0,0 +1,58 @@
+package events
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryPublisher_DeliversToUserAndSagaChannels(t *testing.T) {
+	p := NewMemoryPublisher()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	userEvents := p.Subscribe(ctx, UserChannel("user-1"))
+	sagaEvents := p.Subscribe(ctx, SagaChannel("saga-1"))
+
+	event := Event{
+		EventType: EventTypeOrderValidated,
+		SagaID:    "saga-1",
+		UserID:    "user-1",
+		Timestamp: time.Now(),
+	}
+	err := p.Publish(ctx, event)
+	require.NoError(t, err)
+
+	select {
+	case received := <-userEvents:
+		assert.Equal(t, event.EventType, received.EventType)
+		assert.Equal(t, schemaVersion, received.Version)
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for event on user channel")
+	}
+
+	select {
+	case received := <-sagaEvents:
+		assert.Equal(t, event.EventType, received.EventType)
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for event on saga channel")
+	}
+}
+
+func TestMemoryPublisher_UnsubscribesOnContextCancel(t *testing.T) {
+	p := NewMemoryPublisher()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	ch := p.Subscribe(ctx, UserChannel("user-1"))
+	cancel()
+
+	select {
+	case _, ok := <-ch:
+		assert.False(t, ok, "expected channel to be closed after context cancellation")
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for channel to close")
+	}
+}
@@ -0,0 +1,17 @@
+package events
+
+import "context"
+
+// Publisher publishes saga lifecycle events so any number of subscribers
+// (the WebSocket subsystem, future consumers) can react without the
+// publishing activity knowing about them
+type Publisher interface {
+	Publish(ctx context.Context, event Event) error
+}
+
+// Subscriber opens a long-lived subscription to a pub/sub channel (typically
+// "user:{user_id}" or "saga:{saga_id}"), returning a channel of decoded
+// events. The returned channel is closed when ctx is cancelled.
+type Subscriber interface {
+	Subscribe(ctx context.Context, channel string) <-chan Event
+}
@@ -0,0 +1,123 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog"
+)
+
+const (
+	subscribeMinBackoff = 500 * time.Millisecond
+	subscribeMaxBackoff = 30 * time.Second
+)
+
+// RedisPublisher is a Publisher/Subscriber backed by Redis pub/sub. Every
+// event is published to its user channel, its saga channel, and the
+// broadcast channel, so consumers can pick whichever granularity they need.
+type RedisPublisher struct {
+	client *redis.Client
+	logger zerolog.Logger
+}
+
+// NewRedisPublisher creates a new Redis-backed publisher
+func NewRedisPublisher(addr string, logger zerolog.Logger) *RedisPublisher {
+	return &RedisPublisher{
+		client: redis.NewClient(&redis.Options{Addr: addr}),
+		logger: logger.With().Str("component", "redis_publisher").Logger(),
+	}
+}
+
+// Publish implements Publisher
+func (p *RedisPublisher) Publish(ctx context.Context, event Event) error {
+	event.Version = schemaVersion
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("marshal event: %w", err)
+	}
+
+	if err := p.client.Publish(ctx, UserChannel(event.UserID), data).Err(); err != nil {
+		return fmt.Errorf("publish to user channel: %w", err)
+	}
+	if err := p.client.Publish(ctx, SagaChannel(event.SagaID), data).Err(); err != nil {
+		return fmt.Errorf("publish to saga channel: %w", err)
+	}
+	if err := p.client.Publish(ctx, BroadcastChannel(), data).Err(); err != nil {
+		return fmt.Errorf("publish to broadcast channel: %w", err)
+	}
+
+	return nil
+}
+
+// Subscribe implements Subscriber. The long-lived subscription survives a
+// Redis restart or network blip by reconnecting with exponential backoff
+// instead of giving up; ctx cancellation is the only way it stops for good.
+func (p *RedisPublisher) Subscribe(ctx context.Context, channel string) <-chan Event {
+	out := make(chan Event)
+
+	go func() {
+		defer close(out)
+
+		backoff := subscribeMinBackoff
+		for ctx.Err() == nil {
+			if p.runSubscription(ctx, channel, out) {
+				backoff = subscribeMinBackoff
+				continue
+			}
+
+			select {
+			case <-time.After(backoff):
+			case <-ctx.Done():
+				return
+			}
+			if backoff < subscribeMaxBackoff {
+				backoff *= 2
+				if backoff > subscribeMaxBackoff {
+					backoff = subscribeMaxBackoff
+				}
+			}
+		}
+	}()
+
+	return out
+}
+
+// runSubscription drains one Redis subscription until it drops or ctx is
+// cancelled, returning true if at least one message was delivered (so the
+// caller can reset its backoff before reconnecting)
+func (p *RedisPublisher) runSubscription(ctx context.Context, channel string, out chan<- Event) bool {
+	pubsub := p.client.Subscribe(ctx, channel)
+	defer pubsub.Close()
+
+	delivered := false
+	msgCh := pubsub.Channel()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return delivered
+
+		case msg, ok := <-msgCh:
+			if !ok {
+				return delivered
+			}
+
+			var event Event
+			if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+				p.logger.Error().Err(err).Str("channel", channel).Msg("failed to decode event payload")
+				continue
+			}
+			delivered = true
+
+			select {
+			case out <- event:
+			case <-ctx.Done():
+				return delivered
+			}
+		}
+	}
+}
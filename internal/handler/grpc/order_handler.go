@@ -2,37 +2,67 @@ package grpc
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/rs/zerolog"
 	"github.com/shopspring/decimal"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.temporal.io/api/serviceerror"
 	"go.temporal.io/sdk/client"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
 	orderv1 "github.com/cypherlabdev/cypherlabdev-protos/gen/go/order/v1"
+	"github.com/cypherlabdev/order-validator-service/internal/activity"
+	"github.com/cypherlabdev/order-validator-service/internal/batch"
 	"github.com/cypherlabdev/order-validator-service/internal/models"
+	"github.com/cypherlabdev/order-validator-service/internal/recovery"
+	"github.com/cypherlabdev/order-validator-service/internal/transport/websocket"
 	"github.com/cypherlabdev/order-validator-service/internal/workflow"
 )
 
+// maxBatchBets bounds how many bets PlaceBets accepts in a single call
+const maxBatchBets = 100
+
+// placeBetsConcurrency bounds how many workflow starts PlaceBets attempts at once
+const placeBetsConcurrency = 10
+
 // OrderHandler implements the gRPC ValidatorService server
 type OrderHandler struct {
 	orderv1.UnimplementedValidatorServiceServer
 	temporalClient client.Client
+	eventBus       *websocket.Bus
+	fillRepository activity.FillRepository
 	logger         zerolog.Logger
 }
 
-// NewOrderHandler creates a new order gRPC handler
-func NewOrderHandler(temporalClient client.Client, logger zerolog.Logger) *OrderHandler {
+// NewOrderHandler creates a new order gRPC handler. eventBus is the same bus
+// the WebSocket subsystem subscribes to, so GetOrderStatus and WS subscribers
+// always agree on a saga's latest known state. fillRepository backs the
+// GetFills/GetFillsByOrder settlement query RPCs with a direct read path,
+// the same way eventBus backs GetOrderStatus.
+func NewOrderHandler(temporalClient client.Client, eventBus *websocket.Bus, fillRepository activity.FillRepository, logger zerolog.Logger) *OrderHandler {
 	return &OrderHandler{
 		temporalClient: temporalClient,
+		eventBus:       eventBus,
+		fillRepository: fillRepository,
 		logger:         logger.With().Str("component", "order_handler").Logger(),
 	}
 }
 
 // PlaceBet handles bet placement requests by initiating Temporal workflow
 func (h *OrderHandler) PlaceBet(ctx context.Context, req *orderv1.PlaceBetRequest) (*orderv1.PlaceBetResponse, error) {
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(
+		attribute.String("user_id", req.UserId),
+		attribute.String("event_id", req.EventId),
+		attribute.String("market_id", req.BetType),
+	)
+
 	// Validate request
 	userID, err := uuid.Parse(req.UserId)
 	if err != nil {
@@ -69,6 +99,7 @@ func (h *OrderHandler) PlaceBet(ctx context.Context, req *orderv1.PlaceBetReques
 
 	// Generate saga ID (workflow ID)
 	sagaID := fmt.Sprintf("place-bet-%s", uuid.New().String())
+	span.SetAttributes(attribute.String("saga_id", sagaID))
 
 	// Start Temporal workflow
 	workflowOptions := client.StartWorkflowOptions{
@@ -101,3 +132,398 @@ func (h *OrderHandler) PlaceBet(ctx context.Context, req *orderv1.PlaceBetReques
 		Status:  "processing",
 	}, nil
 }
+
+// BatchPlaceOrders validates and places a batch of orders as a group via
+// BatchPlaceOrdersWorkflow, returning a single saga ID. The workflow itself
+// reports a per-order outcome once it completes; callers that need the
+// outcome should query the workflow result using the returned saga ID.
+func (h *OrderHandler) BatchPlaceOrders(ctx context.Context, req *orderv1.BatchPlaceOrdersRequest) (*orderv1.BatchPlaceOrdersResponse, error) {
+	if len(req.Orders) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "orders must not be empty")
+	}
+
+	orderRequests := make([]*models.PlaceOrderRequest, 0, len(req.Orders))
+	for _, o := range req.Orders {
+		userID, err := uuid.Parse(o.UserId)
+		if err != nil {
+			h.logger.Debug().Str("user_id", o.UserId).Msg("invalid user ID format")
+			return nil, status.Error(codes.InvalidArgument, "invalid user ID format")
+		}
+
+		odds, err := decimal.NewFromString(o.Odds)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid odds format")
+		}
+
+		stake, err := decimal.NewFromString(o.Stake)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid stake format")
+		}
+
+		if o.IdempotencyKey == "" {
+			return nil, status.Error(codes.InvalidArgument, "idempotency_key is required for every order")
+		}
+
+		orderRequests = append(orderRequests, &models.PlaceOrderRequest{
+			UserID:         userID,
+			EventID:        o.EventId,
+			MarketID:       o.MarketId,
+			SelectionID:    o.SelectionId,
+			Side:           models.OrderSide(o.Side),
+			Odds:           odds,
+			Stake:          stake,
+			Currency:       o.Currency,
+			IdempotencyKey: o.IdempotencyKey,
+		})
+	}
+
+	sagaID := fmt.Sprintf("batch-place-orders-%s", uuid.New().String())
+
+	workflowOptions := client.StartWorkflowOptions{
+		ID:        sagaID,
+		TaskQueue: "order-validator",
+	}
+
+	workflowInput := workflow.BatchPlaceOrdersWorkflowInput{
+		OrderRequests: orderRequests,
+		SagaID:        sagaID,
+	}
+
+	we, err := h.temporalClient.ExecuteWorkflow(ctx, workflowOptions, workflow.BatchPlaceOrdersWorkflow, workflowInput)
+	if err != nil {
+		h.logger.Error().Err(err).Str("saga_id", sagaID).Msg("failed to start batch workflow")
+		return nil, status.Error(codes.Internal, "failed to start batch order workflow")
+	}
+
+	h.logger.Info().
+		Str("saga_id", sagaID).
+		Str("workflow_id", we.GetID()).
+		Str("run_id", we.GetRunID()).
+		Int("order_count", len(orderRequests)).
+		Msg("batch order workflow started")
+
+	return &orderv1.BatchPlaceOrdersResponse{
+		SagaId: sagaID,
+		Status: "processing",
+	}, nil
+}
+
+// PlaceBets places up to maxBatchBets bets in parallel, one Temporal workflow
+// per bet, under a single batch-level idempotency key. Workflow starts that
+// fail with a transient error (Unavailable, DeadlineExceeded, or a transient
+// Temporal ServiceError) are retried with backoff; validation-style errors
+// are not. The response reports a per-bet outcome so callers can see exactly
+// which bets succeeded, which failed outright, and which exhausted retries.
+func (h *OrderHandler) PlaceBets(ctx context.Context, req *orderv1.PlaceBetsRequest) (*orderv1.PlaceBetsResponse, error) {
+	if len(req.Bets) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "bets must not be empty")
+	}
+	if len(req.Bets) > maxBatchBets {
+		return nil, status.Errorf(codes.InvalidArgument, "at most %d bets are allowed per batch", maxBatchBets)
+	}
+	if req.IdempotencyKey == "" {
+		return nil, status.Error(codes.InvalidArgument, "idempotency_key is required")
+	}
+
+	results := make([]*orderv1.PlaceBetResult, len(req.Bets))
+
+	batch.Run(len(req.Bets), placeBetsConcurrency, func(i int) {
+		results[i] = h.placeSingleBet(ctx, i, req.IdempotencyKey, req.Bets[i])
+	})
+
+	return &orderv1.PlaceBetsResponse{Results: results}, nil
+}
+
+// placeSingleBet starts one bet's PlaceOrderWorkflow, retrying the start on
+// transient errors. It never returns an error itself; every outcome,
+// including validation failures and exhausted retries, is reported in the
+// returned PlaceBetResult so PlaceBets can report partial success.
+func (h *OrderHandler) placeSingleBet(ctx context.Context, index int, batchIdempotencyKey string, req *orderv1.PlaceBetRequest) *orderv1.PlaceBetResult {
+	userID, err := uuid.Parse(req.UserId)
+	if err != nil {
+		return &orderv1.PlaceBetResult{
+			Index:        int32(index),
+			Status:       "failed",
+			ErrorCode:    "INVALID_ARGUMENT",
+			ErrorMessage: "invalid user ID format",
+		}
+	}
+
+	amount, err := decimal.NewFromString(req.Amount)
+	if err != nil {
+		return &orderv1.PlaceBetResult{
+			Index:        int32(index),
+			Status:       "failed",
+			ErrorCode:    "INVALID_ARGUMENT",
+			ErrorMessage: "invalid amount format",
+		}
+	}
+
+	orderRequest := &models.PlaceOrderRequest{
+		UserID:      userID,
+		EventID:     req.EventId,
+		MarketID:    req.BetType,
+		SelectionID: req.Selection,
+		Side:        models.OrderSideBack,
+		Odds:        decimal.NewFromInt(2),
+		Stake:       amount,
+		Currency:    "USD",
+		// Each bet gets its own key derived from the batch key, since
+		// PlaceOrderWorkflow's internal activities each need a per-order key
+		IdempotencyKey: fmt.Sprintf("%s-%d", batchIdempotencyKey, index),
+	}
+
+	sagaID := fmt.Sprintf("place-bet-%s", uuid.New().String())
+	workflowOptions := client.StartWorkflowOptions{
+		ID:        sagaID,
+		TaskQueue: "order-validator",
+	}
+	workflowInput := workflow.PlaceOrderWorkflowInput{
+		OrderRequest: orderRequest,
+		SagaID:       sagaID,
+	}
+
+	var we client.WorkflowRun
+	startErr := batch.Retry(ctx, batch.DefaultRetryPolicy, isRetryableStartError, func() error {
+		we, err = h.temporalClient.ExecuteWorkflow(ctx, workflowOptions, workflow.PlaceOrderWorkflow, workflowInput)
+		return err
+	})
+
+	if startErr != nil {
+		h.logger.Error().Err(startErr).Int("index", index).Str("saga_id", sagaID).Msg("failed to start bet workflow")
+		errorCode := "START_FAILED"
+		if isRetryableStartError(startErr) {
+			// still transient after the last backoff: the retry schedule ran out
+			errorCode = "RETRIES_EXHAUSTED"
+		}
+		return &orderv1.PlaceBetResult{
+			Index:        int32(index),
+			SagaId:       sagaID,
+			Status:       "failed",
+			ErrorCode:    errorCode,
+			ErrorMessage: startErr.Error(),
+		}
+	}
+
+	h.logger.Info().
+		Int("index", index).
+		Str("saga_id", sagaID).
+		Str("workflow_id", we.GetID()).
+		Str("run_id", we.GetRunID()).
+		Msg("bet workflow started")
+
+	return &orderv1.PlaceBetResult{
+		Index:  int32(index),
+		SagaId: sagaID,
+		Status: "processing",
+	}
+}
+
+// isRetryableStartError reports whether err from ExecuteWorkflow is a
+// transient failure worth retrying, as opposed to a fatal one (e.g. bad
+// workflow input) that will never succeed no matter how many times it's retried
+func isRetryableStartError(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return true
+	}
+
+	var unavailable *serviceerror.Unavailable
+	if errors.As(err, &unavailable) {
+		return true
+	}
+
+	var deadlineExceeded *serviceerror.DeadlineExceeded
+	return errors.As(err, &deadlineExceeded)
+}
+
+// BulkCancel starts a CancelUserOrdersWorkflow to cancel every live order
+// and release every held reservation for a user, for KYC/fraud freezes,
+// market suspension, or a user-initiated "cancel all". It returns
+// immediately with a saga ID; the per-order/per-reservation outcome is
+// available from the workflow's own result once it completes.
+func (h *OrderHandler) BulkCancel(ctx context.Context, req *orderv1.BulkCancelRequest) (*orderv1.BulkCancelResponse, error) {
+	if req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+	if req.Reason == "" {
+		return nil, status.Error(codes.InvalidArgument, "reason is required")
+	}
+
+	sagaID := fmt.Sprintf("bulk-cancel-%s", uuid.New().String())
+
+	workflowOptions := client.StartWorkflowOptions{
+		ID:        sagaID,
+		TaskQueue: "order-validator",
+	}
+
+	workflowInput := workflow.CancelUserOrdersWorkflowInput{
+		UserID:   req.UserId,
+		Currency: req.Currency,
+		MarketID: req.MarketId,
+		Reason:   req.Reason,
+	}
+
+	we, err := h.temporalClient.ExecuteWorkflow(ctx, workflowOptions, workflow.CancelUserOrdersWorkflow, workflowInput)
+	if err != nil {
+		h.logger.Error().Err(err).Str("saga_id", sagaID).Str("user_id", req.UserId).Msg("failed to start bulk cancel workflow")
+		return nil, status.Error(codes.Internal, "failed to start bulk cancel workflow")
+	}
+
+	h.logger.Info().
+		Str("saga_id", sagaID).
+		Str("workflow_id", we.GetID()).
+		Str("run_id", we.GetRunID()).
+		Str("user_id", req.UserId).
+		Str("reason", req.Reason).
+		Msg("bulk cancel workflow started")
+
+	return &orderv1.BulkCancelResponse{
+		SagaId: sagaID,
+		Status: "processing",
+	}, nil
+}
+
+// GetOrderStatus returns the most recently observed lifecycle event for a
+// saga, backed by the same event bus the WebSocket subsystem streams from.
+// If no event has been published yet (e.g. the workflow hasn't reached its
+// first milestone), it returns a "processing" status rather than an error.
+func (h *OrderHandler) GetOrderStatus(ctx context.Context, req *orderv1.GetOrderStatusRequest) (*orderv1.GetOrderStatusResponse, error) {
+	if req.SagaId == "" {
+		return nil, status.Error(codes.InvalidArgument, "saga_id is required")
+	}
+
+	event, ok := h.eventBus.LatestForSaga(req.SagaId)
+	if !ok {
+		return &orderv1.GetOrderStatusResponse{
+			SagaId: req.SagaId,
+			Status: "processing",
+		}, nil
+	}
+
+	return &orderv1.GetOrderStatusResponse{
+		SagaId:  event.SagaID,
+		OrderId: event.OrderID,
+		Status:  string(event.Status),
+		Reason:  event.Reason,
+	}, nil
+}
+
+// RecoverSaga inspects a possibly-stuck saga's SagaStateMachine state and
+// either signals it to resume or to compensate, depending on whether it has
+// crossed its point-of-no-return. It shares its decision logic with
+// RecoveryManager's periodic scan via recovery.Recover, so an operator
+// calling this RPC by hand and the automatic startup scanner always agree
+// on what "recovering" a given saga means.
+func (h *OrderHandler) RecoverSaga(ctx context.Context, req *orderv1.RecoverSagaRequest) (*orderv1.RecoverSagaResponse, error) {
+	if req.SagaId == "" {
+		return nil, status.Error(codes.InvalidArgument, "saga_id is required")
+	}
+
+	result, err := recovery.Recover(ctx, h.temporalClient, req.SagaId)
+	if err != nil {
+		h.logger.Error().Err(err).Str("saga_id", req.SagaId).Msg("failed to recover saga")
+		return nil, status.Errorf(codes.Internal, "recover saga: %v", err)
+	}
+
+	h.logger.Info().
+		Str("saga_id", req.SagaId).
+		Str("action", string(result.Action)).
+		Str("workflow_status", result.WorkflowStatus).
+		Str("saga_state", string(result.SagaState)).
+		Msg("saga recovery attempted")
+
+	return &orderv1.RecoverSagaResponse{
+		SagaId:         result.SagaID,
+		Action:         string(result.Action),
+		WorkflowStatus: result.WorkflowStatus,
+		SagaState:      string(result.SagaState),
+	}, nil
+}
+
+// GetFills returns a user's fills with MatchedAt in [from, to), paginated by
+// cursor, so a client can reconcile its own matched stake and PnL without
+// joining directly against the order-book.
+func (h *OrderHandler) GetFills(ctx context.Context, req *orderv1.GetFillsRequest) (*orderv1.GetFillsResponse, error) {
+	if req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	from := time.Unix(0, 0).UTC()
+	if req.From != "" {
+		parsed, err := time.Parse(time.RFC3339, req.From)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid from timestamp, expected RFC3339")
+		}
+		from = parsed
+	}
+
+	to := time.Now().UTC()
+	if req.To != "" {
+		parsed, err := time.Parse(time.RFC3339, req.To)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid to timestamp, expected RFC3339")
+		}
+		to = parsed
+	}
+
+	fills, nextCursor, err := h.fillRepository.GetFillsByUser(ctx, req.UserId, from, to, req.Cursor, int(req.Limit))
+	if err != nil {
+		h.logger.Error().Err(err).Str("user_id", req.UserId).Msg("failed to list fills")
+		return nil, status.Error(codes.Internal, "failed to list fills")
+	}
+
+	return &orderv1.GetFillsResponse{
+		Fills:      toProtoFills(fills),
+		NextCursor: nextCursor,
+	}, nil
+}
+
+// GetFillsByOrder returns every fill recorded for a single order, in match order
+func (h *OrderHandler) GetFillsByOrder(ctx context.Context, req *orderv1.GetFillsByOrderRequest) (*orderv1.GetFillsByOrderResponse, error) {
+	if req.OrderId == "" {
+		return nil, status.Error(codes.InvalidArgument, "order_id is required")
+	}
+
+	fills, err := h.fillRepository.GetFillsByOrder(ctx, req.OrderId)
+	if err != nil {
+		h.logger.Error().Err(err).Str("order_id", req.OrderId).Msg("failed to list fills for order")
+		return nil, status.Error(codes.Internal, "failed to list fills for order")
+	}
+
+	totalFilledStake, err := h.fillRepository.TotalFilledStake(ctx, req.OrderId)
+	if err != nil {
+		h.logger.Error().Err(err).Str("order_id", req.OrderId).Msg("failed to total filled stake")
+		return nil, status.Error(codes.Internal, "failed to total filled stake")
+	}
+
+	return &orderv1.GetFillsByOrderResponse{
+		Fills:            toProtoFills(fills),
+		TotalFilledStake: totalFilledStake.String(),
+	}, nil
+}
+
+// toProtoFills converts internal Fill models to their proto wire representation
+func toProtoFills(fills []*models.Fill) []*orderv1.Fill {
+	out := make([]*orderv1.Fill, 0, len(fills))
+	for _, f := range fills {
+		pb := &orderv1.Fill{
+			Id:           f.ID.String(),
+			OrderId:      f.OrderID,
+			SagaId:       f.SagaID,
+			UserId:       f.UserID,
+			MatchId:      f.MatchID,
+			Side:         string(f.Side),
+			MatchedOdds:  f.MatchedOdds.String(),
+			MatchedStake: f.MatchedStake.String(),
+			MatchedAt:    f.MatchedAt.Format(time.RFC3339),
+			Pnl:          f.PnL.String(),
+			TxRef:        f.TxRef,
+		}
+		if f.SettledAt != nil {
+			pb.SettledAt = f.SettledAt.Format(time.RFC3339)
+		}
+		out = append(out, pb)
+	}
+	return out
+}
@@ -0,0 +1,64 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/rs/zerolog"
+
+	"github.com/cypherlabdev/order-validator-service/internal/activity"
+)
+
+// RulesHandler exposes the validation rules provider over HTTP so operators
+// can observe and hot-swap stake/odds limits without restarting the service
+type RulesHandler struct {
+	provider activity.RulesProvider
+	logger   zerolog.Logger
+}
+
+// NewRulesHandler creates a new rules HTTP handler
+func NewRulesHandler(provider activity.RulesProvider, logger zerolog.Logger) *RulesHandler {
+	return &RulesHandler{
+		provider: provider,
+		logger:   logger.With().Str("component", "rules_handler").Logger(),
+	}
+}
+
+// GetRules handles GET /rules, returning the currently effective validation
+// rules configuration for observability
+func (h *RulesHandler) GetRules(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(h.provider.Rules()); err != nil {
+		h.logger.Error().Err(err).Msg("failed to encode validation rules")
+		http.Error(w, "failed to encode rules", http.StatusInternalServerError)
+	}
+}
+
+// PushRules handles POST /admin/rules, atomically replacing the effective
+// validation rules with the JSON body
+func (h *RulesHandler) PushRules(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var rules activity.ValidationRulesConfig
+	if err := json.NewDecoder(r.Body).Decode(&rules); err != nil {
+		http.Error(w, "invalid rules payload", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.provider.SetRules(&rules); err != nil {
+		h.logger.Error().Err(err).Msg("failed to update validation rules")
+		http.Error(w, "failed to update rules", http.StatusInternalServerError)
+		return
+	}
+
+	h.logger.Info().Msg("validation rules updated")
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,29 @@
+package ledger
+
+import "fmt"
+
+// UserAvailableAccount is the account holding a user's uncommitted, spendable
+// balance in currency
+func UserAvailableAccount(userID, currency string) string {
+	return fmt.Sprintf("user:%s:available:%s", userID, currency)
+}
+
+// UserReservedAccount is the account holding a user's funds held against an
+// in-flight reservation in currency
+func UserReservedAccount(userID, currency string) string {
+	return fmt.Sprintf("user:%s:reserved:%s", userID, currency)
+}
+
+// HousePnLPendingAccount is the account a reservation's stake moves into once
+// its order settles, pending realized-PnL recognition
+func HousePnLPendingAccount(currency string) string {
+	return fmt.Sprintf("house:pnl_pending:%s", currency)
+}
+
+// OpeningBalanceAccount is the counterparty account for the one-time backfill
+// posting that seeds a user's ledger balance to match wallet-service the
+// first time ReconcileWithWallet sees them, so pre-existing wallet funds
+// aren't mistaken for drift
+func OpeningBalanceAccount(currency string) string {
+	return fmt.Sprintf("ledger:opening_balance:%s", currency)
+}
@@ -0,0 +1,57 @@
+// Package ledger records every saga-driven balance movement as an immutable
+// double-entry posting, independent of wallet-service's own bookkeeping. It
+// exists to give PlaceOrderWorkflow an auditable, replayable financial trail
+// and to let ReconciliationWorkflow catch cases where a wallet-service RPC
+// reports success but its effect never actually lands.
+package ledger
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// Posting is one immutable double-entry row: Amount moves from DebitAccount
+// to CreditAccount. A saga records one posting per money-moving step
+// (reserve, commit, cancel) rather than mutating an account balance directly,
+// so the full history is always reconstructable from postings alone.
+type Posting struct {
+	ID             uuid.UUID
+	SagaID         string
+	IdempotencyKey string
+	DebitAccount   string
+	CreditAccount  string
+	Amount         decimal.Decimal
+	Currency       string
+	Reason         string
+	// CausalRef is the reservation/order ID this posting is tied to, for
+	// tracing a posting back to the wallet/order-book operation that caused it
+	CausalRef string
+	CreatedAt time.Time
+}
+
+// Store persists postings and serves the ledger's read API: projected
+// account balances and a saga's full posting history.
+type Store interface {
+	// RecordPosting appends p, assigning p.ID if unset. It's idempotent on
+	// (SagaID, IdempotencyKey) - recording the same posting twice (e.g. a
+	// retried RecordPostingActivity) returns the original row rather than
+	// double-booking it.
+	RecordPosting(ctx context.Context, p *Posting) error
+
+	// GetAccountBalance sums every posting crediting account minus every
+	// posting debiting it
+	GetAccountBalance(ctx context.Context, account string) (decimal.Decimal, error)
+
+	// GetSagaPostings returns every posting recorded for sagaID, in the order
+	// they were written
+	GetSagaPostings(ctx context.Context, sagaID string) ([]*Posting, error)
+
+	// HasPostings reports whether any posting has ever debited or credited
+	// account, distinguishing "genuinely zero balance" from "never seen" -
+	// the latter needs an opening-balance backfill before it can be
+	// meaningfully reconciled against wallet-service
+	HasPostings(ctx context.Context, account string) (bool, error)
+}
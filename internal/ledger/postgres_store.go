@@ -0,0 +1,147 @@
+package ledger
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// PostgresStore is a Store backed by an append-only Postgres table:
+//
+//	CREATE TABLE postings (
+//	    id              UUID PRIMARY KEY,
+//	    saga_id         TEXT NOT NULL,
+//	    idempotency_key TEXT NOT NULL,
+//	    debit_account   TEXT NOT NULL,
+//	    credit_account  TEXT NOT NULL,
+//	    amount          NUMERIC NOT NULL,
+//	    currency        TEXT NOT NULL,
+//	    reason          TEXT NOT NULL,
+//	    causal_ref      TEXT,
+//	    created_at      TIMESTAMPTZ NOT NULL,
+//	    UNIQUE (saga_id, idempotency_key)
+//	);
+//	CREATE INDEX ON postings (debit_account);
+//	CREATE INDEX ON postings (credit_account);
+//	CREATE INDEX ON postings (saga_id);
+//
+// No UPDATE or DELETE is ever issued against this table - a correction is
+// its own new posting, not a mutation of a prior one, so the row set is
+// always a faithful replay of every balance movement that ever happened.
+type PostgresStore struct {
+	db *sql.DB
+}
+
+// NewPostgresStore creates a new Postgres-backed ledger store
+func NewPostgresStore(db *sql.DB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+// RecordPosting implements Store. The ON CONFLICT DO UPDATE is a no-op write
+// (it sets a column to its own existing value) whose only purpose is making
+// RETURNING id work whether this is the first or a retried call for the same
+// (SagaID, IdempotencyKey).
+func (s *PostgresStore) RecordPosting(ctx context.Context, p *Posting) error {
+	if p.ID == uuid.Nil {
+		p.ID = uuid.New()
+	}
+	if p.CreatedAt.IsZero() {
+		p.CreatedAt = time.Now()
+	}
+
+	err := s.db.QueryRowContext(ctx, `
+		INSERT INTO postings (id, saga_id, idempotency_key, debit_account, credit_account, amount, currency, reason, causal_ref, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (saga_id, idempotency_key) DO UPDATE SET saga_id = postings.saga_id
+		RETURNING id
+	`, p.ID, p.SagaID, p.IdempotencyKey, p.DebitAccount, p.CreditAccount, p.Amount.String(), p.Currency, p.Reason, nullableString(p.CausalRef), p.CreatedAt).
+		Scan(&p.ID)
+	if err != nil {
+		return fmt.Errorf("record posting: %w", err)
+	}
+
+	return nil
+}
+
+// GetAccountBalance implements Store
+func (s *PostgresStore) GetAccountBalance(ctx context.Context, account string) (decimal.Decimal, error) {
+	var credited, debited string
+	err := s.db.QueryRowContext(ctx, `
+		SELECT
+			COALESCE((SELECT SUM(amount) FROM postings WHERE credit_account = $1), 0),
+			COALESCE((SELECT SUM(amount) FROM postings WHERE debit_account = $1), 0)
+	`, account).Scan(&credited, &debited)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("get account balance: %w", err)
+	}
+
+	creditedDec, err := decimal.NewFromString(credited)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("parse credited total: %w", err)
+	}
+	debitedDec, err := decimal.NewFromString(debited)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("parse debited total: %w", err)
+	}
+
+	return creditedDec.Sub(debitedDec), nil
+}
+
+// HasPostings implements Store
+func (s *PostgresStore) HasPostings(ctx context.Context, account string) (bool, error) {
+	var exists bool
+	err := s.db.QueryRowContext(ctx, `
+		SELECT EXISTS(SELECT 1 FROM postings WHERE credit_account = $1 OR debit_account = $1)
+	`, account).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("check account postings: %w", err)
+	}
+
+	return exists, nil
+}
+
+// GetSagaPostings implements Store
+func (s *PostgresStore) GetSagaPostings(ctx context.Context, sagaID string) ([]*Posting, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, saga_id, idempotency_key, debit_account, credit_account, amount, currency, reason, COALESCE(causal_ref, ''), created_at
+		FROM postings
+		WHERE saga_id = $1
+		ORDER BY created_at, id
+	`, sagaID)
+	if err != nil {
+		return nil, fmt.Errorf("get saga postings: %w", err)
+	}
+	defer rows.Close()
+
+	var postings []*Posting
+	for rows.Next() {
+		var p Posting
+		var amount string
+		if err := rows.Scan(&p.ID, &p.SagaID, &p.IdempotencyKey, &p.DebitAccount, &p.CreditAccount,
+			&amount, &p.Currency, &p.Reason, &p.CausalRef, &p.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan posting: %w", err)
+		}
+
+		parsed, err := decimal.NewFromString(amount)
+		if err != nil {
+			return nil, fmt.Errorf("parse posting amount: %w", err)
+		}
+		p.Amount = parsed
+
+		postings = append(postings, &p)
+	}
+
+	return postings, rows.Err()
+}
+
+// nullableString maps an empty string to SQL NULL, since causal_ref is optional
+func nullableString(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// Fill represents one matched portion of an order. A partially-filled order
+// accumulates one Fill per match rather than a single row mutated in place,
+// so the full match timeline for an order can be reconstructed by listing
+// its fills in MatchedAt order.
+type Fill struct {
+	ID           uuid.UUID       `json:"id"`
+	OrderID      string          `json:"order_id"`
+	SagaID       string          `json:"saga_id"`
+	UserID       string          `json:"user_id"`
+	MatchID      string          `json:"match_id,omitempty"` // Order-book counterparty match ID
+	Side         OrderSide       `json:"side"`
+	MatchedOdds  decimal.Decimal `json:"matched_odds"`
+	MatchedStake decimal.Decimal `json:"matched_stake"`
+	MatchedAt    time.Time       `json:"matched_at"`
+	SettledAt    *time.Time      `json:"settled_at,omitempty"`
+	PnL          decimal.Decimal `json:"pnl"`
+	TxRef        string          `json:"tx_ref,omitempty"` // Optional on-chain settlement reference
+}
@@ -28,6 +28,26 @@ const (
 	OrderSideLay  OrderSide = "LAY"  // Betting against outcome
 )
 
+// MarketStatus represents whether a market is currently accepting orders
+type MarketStatus string
+
+const (
+	MarketStatusOpen      MarketStatus = "OPEN"       // Accepting orders
+	MarketStatusSuspended MarketStatus = "SUSPENDED"  // Temporarily not accepting orders (e.g. in-play pause)
+	MarketStatusClosed    MarketStatus = "CLOSED"     // Settled or withdrawn, no longer accepting orders
+	MarketStatusInAuction MarketStatus = "IN_AUCTION" // Matching paused pending an auction uncross
+)
+
+// MarketState is the current tradable state of a market as reported by the
+// order-book/market-data service
+type MarketState struct {
+	MarketID  string          `json:"market_id"`
+	Status    MarketStatus    `json:"status"`
+	BestBid   decimal.Decimal `json:"best_bid"`
+	BestAsk   decimal.Decimal `json:"best_ask"`
+	MarkPrice decimal.Decimal `json:"mark_price"`
+}
+
 // Order represents a bet order in the system
 type Order struct {
 	ID              uuid.UUID       `json:"id"`
@@ -75,8 +95,43 @@ const (
 	ErrOddsOutOfRange   = "odds out of acceptable range"
 	ErrStakeTooLow      = "stake below minimum"
 	ErrStakeTooHigh     = "stake exceeds maximum"
+
+	// Exposure/risk errors
+	ErrExposureExceeded      = "exposure limit exceeded for user/market/selection"
+	ErrConcentrationExceeded = "selection concentration limit exceeded"
+	ErrDailyLossBreach       = "daily loss circuit breaker triggered"
+
+	// Idempotency errors
+	ErrIdempotencyKeyInProgress = "idempotency key already in progress under another workflow"
 )
 
+// IdempotencyStatus represents the lifecycle state of an idempotency record
+type IdempotencyStatus string
+
+const (
+	IdempotencyStatusPending   IdempotencyStatus = "PENDING"   // Claimed, saga is still running
+	IdempotencyStatusCompleted IdempotencyStatus = "COMPLETED" // Saga finished successfully
+	IdempotencyStatusFailed    IdempotencyStatus = "FAILED"    // Saga finished with a terminal failure
+)
+
+// IdempotencyRecord tracks the outcome of a previously-submitted idempotency
+// key so a retried or duplicate request can be answered from cache instead of
+// re-running the underlying saga
+type IdempotencyRecord struct {
+	UserID         string            `json:"user_id"`
+	IdempotencyKey string            `json:"idempotency_key"`
+	WorkflowID     string            `json:"workflow_id"`
+	Status         IdempotencyStatus `json:"status"`
+	FinalResult    string            `json:"final_result,omitempty"` // JSON-encoded workflow result, set once terminal
+	CreatedAt      time.Time         `json:"created_at"`
+	ExpiresAt      time.Time         `json:"expires_at"`
+}
+
+// Expired reports whether the record's TTL has elapsed as of now
+func (r *IdempotencyRecord) Expired(now time.Time) bool {
+	return !r.ExpiresAt.IsZero() && now.After(r.ExpiresAt)
+}
+
 // Validate validates the order request
 func (r *PlaceOrderRequest) Validate() error {
 	// Odds must be greater than 1.0 (even money or better)
@@ -131,3 +186,12 @@ func (o *Order) CalculateRisk() decimal.Decimal {
 	// Lay bet: risk is the liability (stake * odds)
 	return o.Stake.Mul(o.Odds)
 }
+
+// CalculateRisk calculates the amount at risk for a not-yet-placed order,
+// mirroring Order.CalculateRisk
+func (r *PlaceOrderRequest) CalculateRisk() decimal.Decimal {
+	if r.Side == OrderSideBack {
+		return r.Stake
+	}
+	return r.Stake.Mul(r.Odds)
+}
@@ -0,0 +1,95 @@
+package recovery
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog"
+	"go.temporal.io/api/workflowservice/v1"
+	"go.temporal.io/sdk/client"
+)
+
+// placeOrderWorkflowType is the registered workflow.Type name of
+// PlaceOrderWorkflow, used to scope the visibility query to sagas only.
+const placeOrderWorkflowType = "PlaceOrderWorkflow"
+
+// RecoveryManager periodically scans Temporal for PlaceOrderWorkflow
+// executions that have been running longer than StuckThreshold and calls
+// Recover on each one, so stuck/orphaned sagas are sent resume_saga or
+// compensate_saga without an operator having to call RecoverSaga by hand.
+// PlaceOrderWorkflow only logs either signal; neither starts compensation.
+type RecoveryManager struct {
+	client         client.Client
+	namespace      string
+	pollInterval   time.Duration
+	stuckThreshold time.Duration
+	logger         zerolog.Logger
+}
+
+// NewRecoveryManager creates a RecoveryManager. pollInterval controls how
+// often the scan runs; stuckThreshold is how long a saga may run before it's
+// considered a recovery candidate.
+func NewRecoveryManager(c client.Client, namespace string, pollInterval, stuckThreshold time.Duration, logger zerolog.Logger) *RecoveryManager {
+	return &RecoveryManager{
+		client:         c,
+		namespace:      namespace,
+		pollInterval:   pollInterval,
+		stuckThreshold: stuckThreshold,
+		logger:         logger.With().Str("component", "recovery_manager").Logger(),
+	}
+}
+
+// Run blocks, scanning for stuck sagas every pollInterval until ctx is
+// cancelled.
+func (m *RecoveryManager) Run(ctx context.Context) {
+	ticker := time.NewTicker(m.pollInterval)
+	defer ticker.Stop()
+
+	m.logger.Info().Dur("poll_interval", m.pollInterval).Dur("stuck_threshold", m.stuckThreshold).Msg("recovery manager started")
+
+	for {
+		select {
+		case <-ctx.Done():
+			m.logger.Info().Msg("recovery manager stopping")
+			return
+		case <-ticker.C:
+			m.scanOnce(ctx)
+		}
+	}
+}
+
+// scanOnce runs a single recovery scan, logging (but not returning) any
+// error so one failed cycle doesn't stop the manager from trying again on
+// the next tick.
+func (m *RecoveryManager) scanOnce(ctx context.Context) {
+	cutoff := time.Now().Add(-m.stuckThreshold).UTC().Format(time.RFC3339)
+	query := fmt.Sprintf("WorkflowType = '%s' AND ExecutionStatus = 'Running' AND StartTime < '%s'", placeOrderWorkflowType, cutoff)
+
+	resp, err := m.client.ListWorkflow(ctx, &workflowservice.ListWorkflowExecutionsRequest{
+		Namespace: m.namespace,
+		PageSize:  100,
+		Query:     query,
+	})
+	if err != nil {
+		m.logger.Error().Err(err).Msg("failed to list stuck saga candidates")
+		return
+	}
+
+	for _, execution := range resp.GetExecutions() {
+		sagaID := execution.GetExecution().GetWorkflowId()
+		result, err := Recover(ctx, m.client, sagaID)
+		if err != nil {
+			m.logger.Error().Err(err).Str("saga_id", sagaID).Msg("failed to recover stuck saga")
+			continue
+		}
+
+		if result.Action != ActionNone {
+			m.logger.Warn().
+				Str("saga_id", sagaID).
+				Str("action", string(result.Action)).
+				Str("saga_state", string(result.SagaState)).
+				Msg("recovered stuck saga")
+		}
+	}
+}
@@ -0,0 +1,98 @@
+// Package recovery drives saga recovery for PlaceOrderWorkflow executions
+// that have stalled: it inspects a saga's SagaStateMachine state via a
+// Temporal query and either nudges it forward or signals it to compensate.
+// The same Recover func backs both OrderHandler's on-demand RecoverSaga RPC
+// and RecoveryManager's periodic startup scan, so there's exactly one place
+// that decides what "recovering a saga" means.
+package recovery
+
+import (
+	"context"
+	"fmt"
+
+	enumspb "go.temporal.io/api/enums/v1"
+	"go.temporal.io/sdk/client"
+
+	validatorworkflow "github.com/cypherlabdev/order-validator-service/internal/workflow"
+)
+
+// Action describes what Recover did for a saga.
+type Action string
+
+const (
+	// ActionNone means the saga needed no intervention: it's already
+	// terminal, either closed in Temporal or in a terminal SagaState.
+	ActionNone Action = "none"
+
+	// ActionResumed means a resume_saga signal was sent to nudge a
+	// non-terminal, pre-point-of-no-return saga forward.
+	ActionResumed Action = "resumed"
+
+	// ActionCompensating means a compensate_saga signal was sent because
+	// the saga had crossed its point-of-no-return while stuck.
+	ActionCompensating Action = "compensating"
+)
+
+// Result is the outcome of recovering (or inspecting) one saga.
+type Result struct {
+	SagaID string
+	Action Action
+	// WorkflowStatus is the Temporal execution status at the time of the
+	// check (e.g. "Running", "Completed", "Failed").
+	WorkflowStatus string
+	// SagaState is the saga's own state machine state, empty if the
+	// workflow was already closed and couldn't be queried.
+	SagaState validatorworkflow.SagaState
+}
+
+// Recover inspects the saga identified by sagaID and, if it's running but
+// stuck in a non-terminal SagaState, signals it to either resume or
+// compensate depending on whether it has crossed its point-of-no-return.
+func Recover(ctx context.Context, c client.Client, sagaID string) (*Result, error) {
+	desc, err := c.DescribeWorkflowExecution(ctx, sagaID, "")
+	if err != nil {
+		return nil, fmt.Errorf("describe workflow execution %s: %w", sagaID, err)
+	}
+
+	info := desc.GetWorkflowExecutionInfo()
+	workflowStatus := info.GetStatus()
+	result := &Result{
+		SagaID:         sagaID,
+		Action:         ActionNone,
+		WorkflowStatus: workflowStatus.String(),
+	}
+
+	if workflowStatus != enumspb.WORKFLOW_EXECUTION_STATUS_RUNNING {
+		// Already terminal from Temporal's point of view; nothing to recover.
+		return result, nil
+	}
+
+	encodedState, err := c.QueryWorkflow(ctx, sagaID, "", validatorworkflow.SagaStateQueryType)
+	if err != nil {
+		return nil, fmt.Errorf("query saga_state for %s: %w", sagaID, err)
+	}
+
+	var state validatorworkflow.SagaState
+	if err := encodedState.Get(&state); err != nil {
+		return nil, fmt.Errorf("decode saga_state for %s: %w", sagaID, err)
+	}
+	result.SagaState = state
+
+	if state.IsTerminal() {
+		return result, nil
+	}
+
+	if state.PastPointOfNoReturn() {
+		if err := c.SignalWorkflow(ctx, sagaID, "", validatorworkflow.CompensateSagaSignalName, nil); err != nil {
+			return nil, fmt.Errorf("signal compensate_saga for %s: %w", sagaID, err)
+		}
+		result.Action = ActionCompensating
+		return result, nil
+	}
+
+	if err := c.SignalWorkflow(ctx, sagaID, "", validatorworkflow.ResumeSagaSignalName, nil); err != nil {
+		return nil, fmt.Errorf("signal resume_saga for %s: %w", sagaID, err)
+	}
+	result.Action = ActionResumed
+	return result, nil
+}
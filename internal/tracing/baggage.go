@@ -0,0 +1,38 @@
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/baggage"
+)
+
+// sagaIDBaggageKey is the OTel baggage member key used to carry a saga ID
+// across the gRPC boundary into wallet-service/order-book-service's own
+// traces, distinct from the span context itself (see ContextPropagator),
+// which only carries trace/span IDs across the Temporal workflow<->activity
+// boundary and is not visible to those external services.
+const sagaIDBaggageKey = "saga_id"
+
+// WithSagaIDBaggage returns a context carrying sagaID as an OTel baggage
+// member, so any gRPC client instrumented via UnaryClientInterceptor
+// propagates it to the downstream service's trace.
+func WithSagaIDBaggage(ctx context.Context, sagaID string) context.Context {
+	member, err := baggage.NewMember(sagaIDBaggageKey, sagaID)
+	if err != nil {
+		return ctx
+	}
+
+	bag := baggage.FromContext(ctx)
+	bag, err = bag.SetMember(member)
+	if err != nil {
+		return ctx
+	}
+
+	return baggage.ContextWithBaggage(ctx, bag)
+}
+
+// SagaIDFromBaggage returns the saga ID carried in ctx's OTel baggage, or
+// "" if none is set.
+func SagaIDFromBaggage(ctx context.Context) string {
+	return baggage.FromContext(ctx).Member(sagaIDBaggageKey).Value()
+}
@@ -0,0 +1,61 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+)
+
+// maxLoggedBodyLen bounds how much of a request/response body gets attached
+// to a span event, so a large order payload doesn't blow up trace storage.
+const maxLoggedBodyLen = 1024
+
+var grpcClientTracer = Tracer("github.com/cypherlabdev/order-validator-service/internal/tracing")
+
+// UnaryClientInterceptor returns a grpc.UnaryClientInterceptor that wraps
+// every outgoing unary RPC to serviceName (e.g. "wallet-service") in its own
+// client span, tagged with the RPC method, the saga ID carried in ctx's OTel
+// baggage (see WithSagaIDBaggage), and truncated request/response bodies.
+// Pass it alongside grpc.WithStatsHandler(otelgrpc.NewClientHandler()), which
+// only injects/extracts the W3C trace context into gRPC metadata - it does
+// not create a span of its own.
+func UnaryClientInterceptor(serviceName string) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		ctx, span := grpcClientTracer.Start(ctx, method,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(
+				attribute.String("rpc.service", serviceName),
+				attribute.String("rpc.method", method),
+			))
+		defer span.End()
+
+		if sagaID := SagaIDFromBaggage(ctx); sagaID != "" {
+			span.SetAttributes(attribute.String("saga_id", sagaID))
+		}
+		span.AddEvent("rpc.request", trace.WithAttributes(attribute.String("body", truncate(req))))
+
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return err
+		}
+
+		span.AddEvent("rpc.response", trace.WithAttributes(attribute.String("body", truncate(reply))))
+		return nil
+	}
+}
+
+// truncate renders v for a span event, capped at maxLoggedBodyLen so a large
+// message doesn't blow up trace storage.
+func truncate(v interface{}) string {
+	s := fmt.Sprintf("%v", v)
+	if len(s) > maxLoggedBodyLen {
+		return s[:maxLoggedBodyLen] + "...(truncated)"
+	}
+	return s
+}
@@ -0,0 +1,105 @@
+package tracing
+
+import (
+	"context"
+
+	commonpb "go.temporal.io/api/common/v1"
+	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/workflow"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// traceContextKey is the workflow.Context key the active span context is
+// stashed under between ExtractToWorkflow and InjectFromWorkflow
+type traceContextKey string
+
+const propagationContextKey traceContextKey = "otel-span-context"
+
+// headerKey is the Temporal header field carrying the serialized span context
+const headerKey = "otel-trace-context"
+
+// ContextPropagator carries the active OpenTelemetry span context across the
+// gRPC -> workflow -> activity boundary using Temporal's own context
+// propagation hooks, so every activity span chains up to the gRPC request
+// that started the workflow.
+type ContextPropagator struct{}
+
+// NewContextPropagator creates a ContextPropagator. The same instance should
+// be registered on both the Temporal client (so starting a workflow captures
+// the caller's span) and the worker (so activities can extract it).
+func NewContextPropagator() *ContextPropagator {
+	return &ContextPropagator{}
+}
+
+// Inject serializes the span context carried by ctx into the outbound
+// Temporal header. Called by the SDK when a workflow is started or an
+// activity is scheduled from regular Go code.
+func (p *ContextPropagator) Inject(ctx context.Context, writer workflow.HeaderWriter) error {
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+	return writeCarrier(writer, carrier)
+}
+
+// InjectFromWorkflow serializes the span context previously stashed on ctx
+// by ExtractToWorkflow into the outbound header. Called by the SDK whenever
+// the workflow schedules an activity or starts a child workflow.
+func (p *ContextPropagator) InjectFromWorkflow(ctx workflow.Context, writer workflow.HeaderWriter) error {
+	carrier, ok := ctx.Value(propagationContextKey).(propagation.MapCarrier)
+	if !ok {
+		return nil
+	}
+	return writeCarrier(writer, carrier)
+}
+
+// Extract restores the span context carried in the inbound Temporal header
+// onto ctx. Called by the SDK before invoking an activity function.
+func (p *ContextPropagator) Extract(ctx context.Context, reader workflow.HeaderReader) (context.Context, error) {
+	carrier, err := readCarrier(reader)
+	if err != nil || carrier == nil {
+		return ctx, err
+	}
+	return otel.GetTextMapPropagator().Extract(ctx, carrier), nil
+}
+
+// ExtractToWorkflow stashes the span context carried in the inbound Temporal
+// header onto the workflow.Context via workflow.WithValue, so
+// InjectFromWorkflow can find it again for every activity the workflow
+// schedules. Called by the SDK at the start of every workflow execution.
+func (p *ContextPropagator) ExtractToWorkflow(ctx workflow.Context, reader workflow.HeaderReader) (workflow.Context, error) {
+	carrier, err := readCarrier(reader)
+	if err != nil || carrier == nil {
+		return ctx, err
+	}
+	return workflow.WithValue(ctx, propagationContextKey, carrier), nil
+}
+
+func writeCarrier(writer workflow.HeaderWriter, carrier propagation.MapCarrier) error {
+	payload, err := converter.GetDefaultDataConverter().ToPayload(carrier)
+	if err != nil {
+		return err
+	}
+	writer.Set(headerKey, payload)
+	return nil
+}
+
+func readCarrier(reader workflow.HeaderReader) (propagation.MapCarrier, error) {
+	var carrier propagation.MapCarrier
+
+	found := false
+	err := reader.ForEachKey(func(key string, payload *commonpb.Payload) error {
+		if key != headerKey {
+			return nil
+		}
+		found = true
+		return converter.GetDefaultDataConverter().FromPayload(payload, &carrier)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, nil
+	}
+	return carrier, nil
+}
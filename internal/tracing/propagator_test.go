@@ -0,0 +1,166 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	commonpb "go.temporal.io/api/common/v1"
+	"go.temporal.io/sdk/testsuite"
+	temporalworkflow "go.temporal.io/sdk/workflow"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeHeader is a minimal in-memory workflow.HeaderReader/HeaderWriter, good
+// enough to round-trip a ContextPropagator without a real Temporal server.
+type fakeHeader struct {
+	fields map[string]*commonpb.Payload
+}
+
+func newFakeHeader() *fakeHeader {
+	return &fakeHeader{fields: make(map[string]*commonpb.Payload)}
+}
+
+func (h *fakeHeader) Set(field string, value *commonpb.Payload) {
+	h.fields[field] = value
+}
+
+func (h *fakeHeader) ForEachKey(handler func(string, *commonpb.Payload) error) error {
+	for k, v := range h.fields {
+		if err := handler(k, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// withInMemoryTracing installs an SDK tracer provider backed by an in-memory
+// exporter and a tracecontext propagator for the duration of a test, and
+// returns the exporter so the test can inspect recorded spans.
+func withInMemoryTracing(t *testing.T) *tracetest.InMemoryExporter {
+	t.Helper()
+
+	prevTP := otel.GetTracerProvider()
+	prevProp := otel.GetTextMapPropagator()
+
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prevTP)
+		otel.SetTextMapPropagator(prevProp)
+	})
+
+	return exporter
+}
+
+func TestContextPropagator_InjectExtract_PropagatesSpanContext(t *testing.T) {
+	tests := []struct {
+		name        string
+		startParent bool // whether the injecting context carries a live span
+	}{
+		{name: "with active span, child links to parent", startParent: true},
+		{name: "without active span, child starts a new trace", startParent: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exporter := withInMemoryTracing(t)
+			p := NewContextPropagator()
+
+			ctx := context.Background()
+			var parentSpanID, parentTraceID string
+			if tt.startParent {
+				var span trace.Span
+				ctx, span = Tracer("test").Start(ctx, "parent")
+				parentSpanID = span.SpanContext().SpanID().String()
+				parentTraceID = span.SpanContext().TraceID().String()
+				span.End()
+			}
+
+			header := newFakeHeader()
+			require.NoError(t, p.Inject(ctx, header))
+
+			extractedCtx, err := p.Extract(context.Background(), header)
+			require.NoError(t, err)
+
+			_, childSpan := Tracer("test").Start(extractedCtx, "child")
+			childSpan.End()
+
+			if !tt.startParent {
+				assert.False(t, childSpan.SpanContext().IsRemote())
+				return
+			}
+
+			var child tracetest.SpanStub
+			for _, s := range exporter.GetSpans() {
+				if s.Name == "child" {
+					child = s
+				}
+			}
+			require.NotEmpty(t, child.Name, "expected a recorded child span")
+			assert.Equal(t, parentTraceID, child.SpanContext.TraceID().String())
+			assert.Equal(t, parentSpanID, child.Parent.SpanID().String())
+		})
+	}
+}
+
+func TestContextPropagator_ExtractToWorkflow_ChainsActivitySpanToCaller(t *testing.T) {
+	withInMemoryTracing(t)
+	p := NewContextPropagator()
+
+	callerCtx, callerSpan := Tracer("test").Start(context.Background(), "caller")
+	wantTraceID := callerSpan.SpanContext().TraceID().String()
+	wantSpanID := callerSpan.SpanContext().SpanID().String()
+	callerSpan.End()
+
+	inboundHeader := newFakeHeader()
+	require.NoError(t, p.Inject(callerCtx, inboundHeader))
+
+	// roundTripWorkflow mimics what the SDK's ContextPropagator plumbing does
+	// on a real inbound header: ExtractToWorkflow stashes the caller's span
+	// context on the workflow.Context, InjectFromWorkflow re-serializes it
+	// when an activity is scheduled, and the activity's own Extract restores
+	// it before the activity span starts.
+	roundTripWorkflow := func(wfCtx temporalworkflow.Context) (string, error) {
+		extracted, err := p.ExtractToWorkflow(wfCtx, inboundHeader)
+		if err != nil {
+			return "", err
+		}
+
+		activityHeader := newFakeHeader()
+		if err := p.InjectFromWorkflow(extracted, activityHeader); err != nil {
+			return "", err
+		}
+
+		activityCtx, err := p.Extract(context.Background(), activityHeader)
+		if err != nil {
+			return "", err
+		}
+		_, activitySpan := Tracer("test").Start(activityCtx, "activity")
+		defer activitySpan.End()
+		return activitySpan.SpanContext().TraceID().String(), nil
+	}
+
+	var suite testsuite.WorkflowTestSuite
+	env := suite.NewTestWorkflowEnvironment()
+	env.RegisterWorkflow(roundTripWorkflow)
+	env.ExecuteWorkflow(roundTripWorkflow)
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+
+	var gotTraceID string
+	require.NoError(t, env.GetWorkflowResult(&gotTraceID))
+	assert.Equal(t, wantTraceID, gotTraceID)
+	assert.NotEqual(t, wantSpanID, "", "sanity check: caller span id was captured")
+}
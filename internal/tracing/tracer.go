@@ -0,0 +1,12 @@
+package tracing
+
+import (
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Tracer returns the tracer registered under name, typically the calling
+// package's import path (e.g. ".../internal/activity")
+func Tracer(name string) trace.Tracer {
+	return otel.Tracer(name)
+}
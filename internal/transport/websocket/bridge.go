@@ -0,0 +1,43 @@
+package websocket
+
+import (
+	"context"
+
+	"github.com/rs/zerolog"
+
+	"github.com/cypherlabdev/order-validator-service/internal/events"
+)
+
+// statusByEventType maps the saga lifecycle events published by activities
+// onto the WebSocket subsystem's own EventStatus values
+var statusByEventType = map[events.EventType]EventStatus{
+	events.EventTypeOrderValidated: EventOrderValidated,
+	events.EventTypeFundsReserved:  EventFundsReserved,
+	events.EventTypeOrderPlaced:    EventOrderPlaced,
+	events.EventTypeOrderMatched:   EventOrderMatched,
+	events.EventTypeOrderCancelled: EventOrderCancelled,
+	events.EventTypeSagaFailed:     EventSagaFailed,
+	events.EventTypeOrderFilled:    EventOrderFilled,
+}
+
+// BridgeEvents subscribes to sub's broadcast channel and forwards every
+// event onto bus, keyed by its user ID, so WebSocket subscribers see saga
+// lifecycle events regardless of which Publisher implementation is in use.
+// It blocks until ctx is cancelled.
+func BridgeEvents(ctx context.Context, sub events.Subscriber, bus *Bus, logger zerolog.Logger) {
+	for event := range sub.Subscribe(ctx, events.BroadcastChannel()) {
+		status, ok := statusByEventType[event.EventType]
+		if !ok {
+			logger.Warn().Str("event_type", string(event.EventType)).Msg("unrecognized event type, dropping")
+			continue
+		}
+
+		bus.Publish(event.UserID, OrderEvent{
+			SagaID:    event.SagaID,
+			OrderID:   event.OrderID,
+			Status:    status,
+			Timestamp: event.Timestamp,
+			Reason:    event.Reason,
+		})
+	}
+}
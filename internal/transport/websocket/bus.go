@@ -0,0 +1,74 @@
+package websocket
+
+import "sync"
+
+// Bus fans OrderEvents out per user and retains the latest event per saga, so
+// a late subscriber (or the gRPC status RPC) can answer with the current
+// state instead of only future updates. BridgeEvents publishes into the bus
+// the events workflow activities send through the events Publisher; Handler's
+// connections subscribe to it to push events over WebSocket.
+type Bus struct {
+	mu           sync.RWMutex
+	subscribers  map[string][]chan OrderEvent // userID -> subscriber channels
+	latestBySaga map[string]OrderEvent
+}
+
+// NewBus creates a new, empty event bus
+func NewBus() *Bus {
+	return &Bus{
+		subscribers:  make(map[string][]chan OrderEvent),
+		latestBySaga: make(map[string]OrderEvent),
+	}
+}
+
+// Publish records event as the latest state for its saga and fans it out to
+// every subscriber currently registered for userID. Delivery is best-effort:
+// a subscriber whose buffer is full is skipped rather than blocking the publisher.
+func (b *Bus) Publish(userID string, event OrderEvent) {
+	b.mu.Lock()
+	b.latestBySaga[event.SagaID] = event
+	subs := append([]chan OrderEvent(nil), b.subscribers[userID]...)
+	b.mu.Unlock()
+
+	for _, ch := range subs {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+// Subscribe registers a buffered channel to receive future events for
+// userID. The returned function unsubscribes and closes the channel.
+func (b *Bus) Subscribe(userID string, buffer int) (<-chan OrderEvent, func()) {
+	ch := make(chan OrderEvent, buffer)
+
+	b.mu.Lock()
+	b.subscribers[userID] = append(b.subscribers[userID], ch)
+	b.mu.Unlock()
+
+	unsubscribe := func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		subs := b.subscribers[userID]
+		for i, c := range subs {
+			if c == ch {
+				b.subscribers[userID] = append(subs[:i], subs[i+1:]...)
+				break
+			}
+		}
+		close(ch)
+	}
+
+	return ch, unsubscribe
+}
+
+// LatestForSaga returns the most recently published event for sagaID, if any
+func (b *Bus) LatestForSaga(sagaID string) (OrderEvent, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	event, ok := b.latestBySaga[sagaID]
+	return event, ok
+}
@@ -0,0 +1,93 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/rs/zerolog"
+)
+
+const (
+	writeWait      = 10 * time.Second
+	pongWait       = 60 * time.Second
+	pingPeriod     = (pongWait * 9) / 10
+	sendBufferSize = 16
+)
+
+// connection wraps a single WebSocket client's subscription to one user's order events
+type connection struct {
+	userID string
+	ws     *websocket.Conn
+	send   chan OrderEvent
+	logger zerolog.Logger
+}
+
+func newConnection(userID string, ws *websocket.Conn, logger zerolog.Logger) *connection {
+	return &connection{
+		userID: userID,
+		ws:     ws,
+		send:   make(chan OrderEvent, sendBufferSize),
+		logger: logger.With().Str("component", "ws_connection").Str("user_id", userID).Logger(),
+	}
+}
+
+// readPump discards client messages - this is a push-only stream - but keeps
+// the pong handler alive so the heartbeat can detect a dead connection
+func (c *connection) readPump() {
+	defer c.ws.Close()
+
+	c.ws.SetReadDeadline(time.Now().Add(pongWait))
+	c.ws.SetPongHandler(func(string) error {
+		c.ws.SetReadDeadline(time.Now().Add(pongWait))
+		return nil
+	})
+
+	for {
+		if _, _, err := c.ws.ReadMessage(); err != nil {
+			return
+		}
+	}
+}
+
+// writePump drains the send buffer to the client and sends periodic
+// heartbeat pings, closing the connection on write failure or ctx cancellation
+func (c *connection) writePump(ctx context.Context) {
+	ticker := time.NewTicker(pingPeriod)
+	defer func() {
+		ticker.Stop()
+		c.ws.Close()
+	}()
+
+	for {
+		select {
+		case event, ok := <-c.send:
+			c.ws.SetWriteDeadline(time.Now().Add(writeWait))
+			if !ok {
+				_ = c.ws.WriteMessage(websocket.CloseMessage, []byte{})
+				return
+			}
+
+			data, err := json.Marshal(event)
+			if err != nil {
+				c.logger.Error().Err(err).Msg("failed to marshal order event")
+				continue
+			}
+			if err := c.ws.WriteMessage(websocket.TextMessage, data); err != nil {
+				return
+			}
+
+		case <-ticker.C:
+			c.ws.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.ws.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+
+		case <-ctx.Done():
+			c.ws.SetWriteDeadline(time.Now().Add(writeWait))
+			_ = c.ws.WriteMessage(websocket.CloseMessage, []byte{})
+			return
+		}
+	}
+}
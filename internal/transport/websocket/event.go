@@ -0,0 +1,26 @@
+package websocket
+
+import "time"
+
+// EventStatus enumerates the saga/order lifecycle events streamed to subscribers
+type EventStatus string
+
+const (
+	EventOrderValidated EventStatus = "order.validated"
+	EventFundsReserved  EventStatus = "funds.reserved"
+	EventOrderPlaced    EventStatus = "order.placed"
+	EventOrderMatched   EventStatus = "order.matched"
+	EventOrderCancelled EventStatus = "order.cancelled"
+	EventSagaFailed     EventStatus = "saga.failed"
+	EventOrderFilled    EventStatus = "order.filled"
+)
+
+// OrderEvent is a single saga/order lifecycle update, streamed to WebSocket
+// subscribers and served back by the gRPC status RPC
+type OrderEvent struct {
+	SagaID    string      `json:"saga_id"`
+	OrderID   string      `json:"order_id,omitempty"`
+	Status    EventStatus `json:"status"`
+	Timestamp time.Time   `json:"timestamp"`
+	Reason    string      `json:"reason,omitempty"`
+}
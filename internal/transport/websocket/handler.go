@@ -0,0 +1,97 @@
+package websocket
+
+import (
+	"net/http"
+
+	"github.com/gorilla/websocket"
+	"github.com/rs/zerolog"
+)
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	// TODO: restrict to a configured origin allowlist once the frontend domain is known
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// Handler upgrades HTTP connections to WebSocket and streams a user's
+// order/saga lifecycle events from the shared Bus until the client
+// disconnects or the request is cancelled
+type Handler struct {
+	bus       *Bus
+	validator TokenValidator
+	logger    zerolog.Logger
+}
+
+// NewHandler creates a new WebSocket handler backed by bus, authenticating
+// connecting clients with validator
+func NewHandler(bus *Bus, validator TokenValidator, logger zerolog.Logger) *Handler {
+	return &Handler{
+		bus:       bus,
+		validator: validator,
+		logger:    logger.With().Str("component", "ws_handler").Logger(),
+	}
+}
+
+// ServeHTTP handles GET /ws/orders?user_id=...&token=..., authenticating
+// token and upgrading the connection to stream that user's order events
+// until the client disconnects. token must have been issued for user_id -
+// a caller authenticated as one user cannot stream another user's events.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.URL.Query().Get("user_id")
+	if userID == "" {
+		http.Error(w, "user_id is required", http.StatusBadRequest)
+		return
+	}
+
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		http.Error(w, "token is required", http.StatusUnauthorized)
+		return
+	}
+
+	authedUserID, err := h.validator.ValidateToken(r.Context(), token)
+	if err != nil {
+		h.logger.Warn().Err(err).Msg("websocket auth failed")
+		http.Error(w, "invalid token", http.StatusUnauthorized)
+		return
+	}
+	if authedUserID != userID {
+		h.logger.Warn().Str("user_id", userID).Msg("websocket token does not authorize requested user_id")
+		http.Error(w, "token does not authorize this user_id", http.StatusForbidden)
+		return
+	}
+
+	ws, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		h.logger.Error().Err(err).Msg("failed to upgrade websocket connection")
+		return
+	}
+
+	conn := newConnection(userID, ws, h.logger)
+	events, unsubscribe := h.bus.Subscribe(userID, sendBufferSize)
+	defer unsubscribe()
+
+	go conn.readPump()
+
+	ctx := r.Context()
+	go func() {
+		for {
+			select {
+			case event, ok := <-events:
+				if !ok {
+					return
+				}
+				select {
+				case conn.send <- event:
+				default:
+					h.logger.Warn().Str("user_id", userID).Msg("dropping order event: send buffer full")
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	conn.writePump(ctx)
+}
@@ -0,0 +1,123 @@
+package websocket
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testAuthSecret = "test-secret"
+
+func setupTestServer(t *testing.T) (*Bus, string) {
+	bus := NewBus()
+	handler := NewHandler(bus, NewHMACTokenValidator(testAuthSecret), zerolog.Nop())
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http")
+	return bus, wsURL
+}
+
+// tokenFor signs a valid token for userID using the test server's secret
+func tokenFor(userID string) string {
+	mac := hmac.New(sha256.New, []byte(testAuthSecret))
+	mac.Write([]byte(userID))
+	return userID + "." + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestHandler_RequiresUserID(t *testing.T) {
+	_, wsURL := setupTestServer(t)
+
+	_, resp, err := websocket.DefaultDialer.Dial(wsURL+"?token="+tokenFor("user-1"), nil)
+	require.Error(t, err)
+	require.NotNil(t, resp)
+	assert.Equal(t, 400, resp.StatusCode)
+}
+
+func TestHandler_RequiresValidToken(t *testing.T) {
+	_, wsURL := setupTestServer(t)
+
+	_, resp, err := websocket.DefaultDialer.Dial(wsURL+"?user_id=user-1&token=garbage", nil)
+	require.Error(t, err)
+	require.NotNil(t, resp)
+	assert.Equal(t, 401, resp.StatusCode)
+}
+
+func TestHandler_RejectsTokenForDifferentUser(t *testing.T) {
+	_, wsURL := setupTestServer(t)
+
+	_, resp, err := websocket.DefaultDialer.Dial(wsURL+"?user_id=user-1&token="+tokenFor("user-2"), nil)
+	require.Error(t, err)
+	require.NotNil(t, resp)
+	assert.Equal(t, 403, resp.StatusCode)
+}
+
+func TestHandler_StreamsPublishedEvents(t *testing.T) {
+	bus, wsURL := setupTestServer(t)
+
+	conn, _, err := websocket.DefaultDialer.Dial(wsURL+"?user_id=user-1&token="+tokenFor("user-1"), nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// Give the server a moment to register the subscription before publishing
+	time.Sleep(50 * time.Millisecond)
+
+	event := OrderEvent{
+		SagaID:    "saga-1",
+		OrderID:   "order-1",
+		Status:    EventOrderValidated,
+		Timestamp: time.Now(),
+	}
+	bus.Publish("user-1", event)
+
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var received OrderEvent
+	err = conn.ReadJSON(&received)
+	require.NoError(t, err)
+
+	assert.Equal(t, event.SagaID, received.SagaID)
+	assert.Equal(t, event.OrderID, received.OrderID)
+	assert.Equal(t, event.Status, received.Status)
+}
+
+func TestHandler_DoesNotDeliverEventsForOtherUsers(t *testing.T) {
+	bus, wsURL := setupTestServer(t)
+
+	conn, _, err := websocket.DefaultDialer.Dial(wsURL+"?user_id=user-1&token="+tokenFor("user-1"), nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	time.Sleep(50 * time.Millisecond)
+
+	bus.Publish("user-2", OrderEvent{SagaID: "saga-2", Status: EventOrderPlaced, Timestamp: time.Now()})
+
+	conn.SetReadDeadline(time.Now().Add(200 * time.Millisecond))
+	var received OrderEvent
+	err = conn.ReadJSON(&received)
+	assert.Error(t, err) // expect a read timeout, not a delivered event
+}
+
+func TestBus_LatestForSaga(t *testing.T) {
+	bus := NewBus()
+
+	_, ok := bus.LatestForSaga("saga-1")
+	assert.False(t, ok)
+
+	event := OrderEvent{SagaID: "saga-1", Status: EventSagaFailed, Reason: "validation failed", Timestamp: time.Now()}
+	bus.Publish("user-1", event)
+
+	latest, ok := bus.LatestForSaga("saga-1")
+	require.True(t, ok)
+	assert.Equal(t, event.Status, latest.Status)
+	assert.Equal(t, event.Reason, latest.Reason)
+}
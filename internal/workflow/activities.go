@@ -1,6 +1,8 @@
 package workflow
 
 import (
+	"github.com/shopspring/decimal"
+
 	"github.com/cypherlabdev/order-validator-service/internal/models"
 )
 
@@ -9,6 +11,12 @@ import (
 // ValidateOrderInput is input for ValidateOrderActivity
 type ValidateOrderInput struct {
 	OrderRequest *models.PlaceOrderRequest
+	// MarkPrice is the current mark-price-implied odds for the order's
+	// selection, used for the odds sanity band check. Zero means "unknown"
+	// (e.g. market state lookup was skipped) and the check is not applied.
+	MarkPrice decimal.Decimal
+	// SagaID identifies the workflow run, used to publish the ORDER_VALIDATED event
+	SagaID string
 }
 
 // ValidationResult is output for ValidateOrderActivity
@@ -89,3 +97,249 @@ type CancelOrderInput struct {
 type CancelOrderResult struct {
 	Status string
 }
+
+// BatchValidateOrdersInput is input for BatchValidateOrdersActivity
+type BatchValidateOrdersInput struct {
+	OrderRequests []*models.PlaceOrderRequest
+	// MarkPrices is each order's current mark-price-implied odds, keyed by
+	// IdempotencyKey, used for the odds sanity band check. A missing entry
+	// means "unknown" and the check is not applied, same as a zero MarkPrice
+	// in ValidateOrderInput.
+	MarkPrices map[string]decimal.Decimal
+}
+
+// BatchValidationResult is output for BatchValidateOrdersActivity, keyed by
+// each order's IdempotencyKey
+type BatchValidationResult struct {
+	Results map[string]*ValidationResult
+}
+
+// GetMarketStateInput is input for GetMarketStateActivity
+type GetMarketStateInput struct {
+	MarketID string
+}
+
+// GetMarketStateResult is output for GetMarketStateActivity
+type GetMarketStateResult struct {
+	State *models.MarketState
+}
+
+// InvalidateMarketStateInput is input for InvalidateMarketStateActivity
+type InvalidateMarketStateInput struct {
+	MarketID string
+}
+
+// InvalidateMarketStateResult is output for InvalidateMarketStateActivity
+type InvalidateMarketStateResult struct {
+	Invalidated bool
+}
+
+// CheckExposureInput is input for CheckExposureActivity
+type CheckExposureInput struct {
+	OrderRequest *models.PlaceOrderRequest
+}
+
+// CheckExposureResult is output for CheckExposureActivity
+type CheckExposureResult struct {
+	Allowed bool
+	Reason  string
+}
+
+// ClaimIdempotencyKeyInput is input for ClaimIdempotencyKeyActivity
+type ClaimIdempotencyKeyInput struct {
+	UserID         string
+	IdempotencyKey string
+	WorkflowID     string
+}
+
+// ClaimIdempotencyKeyResult is output for ClaimIdempotencyKeyActivity. Exactly
+// one of FirstSeen, InProgress being true, or FinalResult being set describes
+// the outcome of the claim.
+type ClaimIdempotencyKeyResult struct {
+	// FirstSeen is true if this call claimed the key for WorkflowID
+	FirstSeen bool
+	// InProgress is true if the key is PENDING under a different, still-running workflow
+	InProgress bool
+	// WorkflowID is the ID of the workflow currently holding the key (set whenever FirstSeen is false)
+	WorkflowID string
+	// FinalResult is the JSON-encoded PlaceOrderWorkflowResult from the run that
+	// originally completed this key, set only once that run reached a terminal state
+	FinalResult string
+}
+
+// RecordIdempotencyResultInput is input for RecordIdempotencyResultActivity
+type RecordIdempotencyResultInput struct {
+	UserID         string
+	IdempotencyKey string
+	Success        bool
+	FinalResult    string // JSON-encoded PlaceOrderWorkflowResult
+}
+
+// RecordIdempotencyResultResult is output for RecordIdempotencyResultActivity
+type RecordIdempotencyResultResult struct{}
+
+// RecordFillInput is input for RecordFillActivity
+type RecordFillInput struct {
+	OrderID      string
+	SagaID       string
+	UserID       string
+	MatchID      string // From PlaceOrderInBookResult, identifies the counterparty match
+	Side         string
+	MatchedOdds  string
+	MatchedStake string
+}
+
+// RecordFillResult is output for RecordFillActivity
+type RecordFillResult struct {
+	FillID string
+}
+
+// GetCumulativePnLInput is input for GetCumulativePnLActivity
+type GetCumulativePnLInput struct {
+	OrderIDs []string
+}
+
+// GetCumulativePnLResult is output for GetCumulativePnLActivity
+type GetCumulativePnLResult struct {
+	CumulativePnL string
+}
+
+// RecoverOrdersInput is input for RecoverOrdersActivity
+type RecoverOrdersInput struct {
+	UserID      string
+	MarketID    string
+	SelectionID string
+}
+
+// RecoverOrdersResult is output for RecoverOrdersActivity. OpenRisk is the
+// order-book's own view of the user's outstanding risk on this selection,
+// for a caller like ScheduledOrderWorkflow to reconcile against what it
+// believes it has outstanding after a restart.
+type RecoverOrdersResult struct {
+	OpenRisk string
+}
+
+// CancelOrdersForUserInput is input for CancelOrdersForUserActivity. Currency
+// and MarketID are optional filters; empty means "every currency"/"every market".
+type CancelOrdersForUserInput struct {
+	UserID   string
+	Currency string
+	MarketID string
+	SagaID   string
+	Reason   string
+}
+
+// OrderCancelStatus is one order's outcome within a CancelOrdersForUserActivity
+// result. An order that has already settled or was already cancelled is
+// reported here with Cancelled false and a FailureReason describing why,
+// rather than failing the whole activity.
+type OrderCancelStatus struct {
+	OrderID       string
+	Cancelled     bool
+	FailureReason string
+}
+
+// CancelOrdersForUserResult is output for CancelOrdersForUserActivity, one
+// OrderCancelStatus per live order found for the user
+type CancelOrdersForUserResult struct {
+	Statuses []*OrderCancelStatus
+}
+
+// CancelReservationsForUserInput is input for CancelReservationsForUserActivity.
+// Currency and MarketID are optional filters; empty means "every
+// currency"/"every market".
+type CancelReservationsForUserInput struct {
+	UserID   string
+	Currency string
+	MarketID string
+	SagaID   string
+	Reason   string
+}
+
+// ReservationCancelStatus is one reservation's outcome within a
+// CancelReservationsForUserActivity result
+type ReservationCancelStatus struct {
+	ReservationID string
+	Cancelled     bool
+	FailureReason string
+}
+
+// CancelReservationsForUserResult is output for CancelReservationsForUserActivity,
+// one ReservationCancelStatus per still-held reservation found for the user
+type CancelReservationsForUserResult struct {
+	Statuses []*ReservationCancelStatus
+}
+
+// RecordPostingInput is input for RecordPostingActivity. DebitAccount and
+// CreditAccount are ledger account identifiers (see internal/ledger),
+// typically built with ledger.UserAvailableAccount and friends.
+type RecordPostingInput struct {
+	SagaID         string
+	IdempotencyKey string
+	DebitAccount   string
+	CreditAccount  string
+	Amount         string
+	Currency       string
+	Reason         string
+	// CausalRef is the reservation/order ID this posting is tied to
+	CausalRef string
+}
+
+// RecordPostingResult is output for RecordPostingActivity
+type RecordPostingResult struct {
+	PostingID string
+}
+
+// PostingSummary is a lightweight record of one posting RecordPostingActivity
+// made, tracked locally by PlaceOrderWorkflow so its ledger_postings query
+// can answer without making a blocking activity call of its own
+type PostingSummary struct {
+	PostingID     string
+	DebitAccount  string
+	CreditAccount string
+	Amount        string
+	Currency      string
+	Reason        string
+	CausalRef     string
+}
+
+// GetAccountBalanceInput is input for GetAccountBalanceActivity
+type GetAccountBalanceInput struct {
+	Account string
+}
+
+// GetAccountBalanceResult is output for GetAccountBalanceActivity
+type GetAccountBalanceResult struct {
+	Balance string
+}
+
+// GetSagaPostingsInput is input for GetSagaPostingsActivity
+type GetSagaPostingsInput struct {
+	SagaID string
+}
+
+// GetSagaPostingsResult is output for GetSagaPostingsActivity
+type GetSagaPostingsResult struct {
+	Postings []*PostingSummary
+}
+
+// ReconcileWithWalletInput is input for ReconcileWithWalletActivity
+type ReconcileWithWalletInput struct {
+	UserID   string
+	Currency string
+}
+
+// AccountDrift describes a mismatch between the ledger's projected balance
+// for an account and wallet-service's authoritative balance for it
+type AccountDrift struct {
+	Account       string
+	LedgerBalance string
+	WalletBalance string
+	Difference    string // LedgerBalance - WalletBalance
+}
+
+// ReconcileWithWalletResult is output for ReconcileWithWalletActivity. An
+// empty Drifts means the ledger and wallet-service agree on every account checked.
+type ReconcileWithWalletResult struct {
+	Drifts []*AccountDrift
+}
@@ -0,0 +1,308 @@
+package workflow
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/cypherlabdev/order-validator-service/internal/models"
+)
+
+// BatchOrderOutcomeStatus describes the terminal state of a single order
+// within a BatchPlaceOrdersWorkflow run
+type BatchOrderOutcomeStatus string
+
+const (
+	BatchOrderAccepted         BatchOrderOutcomeStatus = "ACCEPTED"
+	BatchOrderRejected         BatchOrderOutcomeStatus = "REJECTED"
+	BatchOrderRetryableFailure BatchOrderOutcomeStatus = "RETRYABLE_FAILURE"
+)
+
+// BatchPlaceOrdersWorkflowInput is the input for BatchPlaceOrdersWorkflow
+type BatchPlaceOrdersWorkflowInput struct {
+	OrderRequests []*models.PlaceOrderRequest
+	SagaID        string
+	MaxRetries    int // per-order retry attempts for retryable activity failures
+}
+
+// BatchOrderOutcome is the per-order outcome of a BatchPlaceOrdersWorkflow run
+type BatchOrderOutcome struct {
+	Status          BatchOrderOutcomeStatus
+	OrderID         string
+	ReservationID   string
+	MatchID         string
+	ValidationError string
+	FailureReason   string
+}
+
+// BatchPlaceOrderResult is the result of BatchPlaceOrdersWorkflow, keyed by
+// each input order's IdempotencyKey so callers can reconcile partial success
+type BatchPlaceOrderResult struct {
+	Outcomes map[string]*BatchOrderOutcome
+}
+
+// BatchPlaceOrdersWorkflow validates a batch of orders, then reserves funds
+// and places every accepted order in the order book concurrently.
+//
+// Steps:
+//  1. Validate every order in one BatchValidateOrdersActivity call
+//  2. For each order that passes validation, reserve funds and place the
+//     order, retrying only the reserve/place/commit steps on transient
+//     activity failures - already-accepted orders are never re-validated
+//  3. Collect a per-order outcome so the caller can act on partial success
+//
+// A single failing order never aborts the rest of the batch.
+func BatchPlaceOrdersWorkflow(ctx workflow.Context, input BatchPlaceOrdersWorkflowInput) (*BatchPlaceOrderResult, error) {
+	logger := workflow.GetLogger(ctx)
+	logger.Info("BatchPlaceOrdersWorkflow started", "saga_id", input.SagaID, "order_count", len(input.OrderRequests))
+
+	maxRetries := input.MaxRetries
+	if maxRetries <= 0 {
+		maxRetries = 3
+	}
+
+	activityOptions := workflow.ActivityOptions{
+		StartToCloseTimeout: 10 * time.Second,
+		RetryPolicy: &workflow.RetryPolicy{
+			InitialInterval:    time.Second,
+			BackoffCoefficient: 2.0,
+			MaximumInterval:    time.Minute,
+			MaximumAttempts:    int32(maxRetries),
+		},
+	}
+	ctx = workflow.WithActivityOptions(ctx, activityOptions)
+
+	result := &BatchPlaceOrderResult{
+		Outcomes: make(map[string]*BatchOrderOutcome, len(input.OrderRequests)),
+	}
+
+	// Step 0: fetch each distinct market's state once, so closed markets can
+	// be rejected outright and every order's odds can be checked against its
+	// own market's mark price during validation
+	marketStates := make(map[string]*models.MarketState, len(input.OrderRequests))
+	for _, req := range input.OrderRequests {
+		if _, ok := marketStates[req.MarketID]; ok {
+			continue
+		}
+		var marketStateResult *GetMarketStateResult
+		if err := workflow.ExecuteActivity(ctx, GetMarketStateActivity, GetMarketStateInput{
+			MarketID: req.MarketID,
+		}).Get(ctx, &marketStateResult); err != nil {
+			logger.Warn("batch: market state lookup failed", "market_id", req.MarketID, "error", err)
+			continue
+		}
+		marketStates[req.MarketID] = marketStateResult.State
+	}
+
+	markPrices := make(map[string]decimal.Decimal, len(input.OrderRequests))
+	for _, req := range input.OrderRequests {
+		if state, ok := marketStates[req.MarketID]; ok {
+			markPrices[req.IdempotencyKey] = state.MarkPrice
+		}
+	}
+
+	// Step 1: validate every order in one activity call
+	var validationResult *BatchValidationResult
+	err := workflow.ExecuteActivity(ctx, BatchValidateOrdersActivity, BatchValidateOrdersInput{
+		OrderRequests: input.OrderRequests,
+		MarkPrices:    markPrices,
+	}).Get(ctx, &validationResult)
+	if err != nil {
+		return nil, fmt.Errorf("batch validation failed: %w", err)
+	}
+
+	// Step 2: reserve + place each accepted order concurrently, independently
+	var futures []workflow.Future
+	type pending struct {
+		req    *models.PlaceOrderRequest
+		future workflow.Future
+	}
+	var pendingOrders []pending
+
+	for _, req := range input.OrderRequests {
+		state, ok := marketStates[req.MarketID]
+		if !ok {
+			result.Outcomes[req.IdempotencyKey] = &BatchOrderOutcome{
+				Status:          BatchOrderRejected,
+				ValidationError: "market state lookup failed",
+			}
+			continue
+		}
+		if state.Status != models.MarketStatusOpen {
+			result.Outcomes[req.IdempotencyKey] = &BatchOrderOutcome{
+				Status:          BatchOrderRejected,
+				ValidationError: models.ErrMarketClosed,
+			}
+			continue
+		}
+
+		validation := validationResult.Results[req.IdempotencyKey]
+		if validation == nil || !validation.Valid {
+			reason := "validation result missing"
+			if validation != nil {
+				reason = validation.Reason
+			}
+			result.Outcomes[req.IdempotencyKey] = &BatchOrderOutcome{
+				Status:          BatchOrderRejected,
+				ValidationError: reason,
+			}
+			continue
+		}
+
+		req := req
+		future, settable := workflow.NewFuture(ctx)
+		workflow.Go(ctx, func(gCtx workflow.Context) {
+			outcome := placeSingleOrderInBatch(gCtx, req, input.SagaID)
+			settable.Set(outcome, nil)
+		})
+		futures = append(futures, future)
+		pendingOrders = append(pendingOrders, pending{req: req, future: future})
+	}
+
+	for _, p := range pendingOrders {
+		var outcome *BatchOrderOutcome
+		if err := p.future.Get(ctx, &outcome); err != nil {
+			outcome = &BatchOrderOutcome{
+				Status:        BatchOrderRetryableFailure,
+				FailureReason: err.Error(),
+			}
+		}
+		result.Outcomes[p.req.IdempotencyKey] = outcome
+	}
+
+	logger.Info("BatchPlaceOrdersWorkflow completed", "saga_id", input.SagaID, "outcomes", len(result.Outcomes))
+
+	return result, nil
+}
+
+// placeSingleOrderInBatch reserves funds and places one already-validated
+// order, returning its terminal outcome without ever touching validation
+func placeSingleOrderInBatch(ctx workflow.Context, req *models.PlaceOrderRequest, sagaID string) *BatchOrderOutcome {
+	logger := workflow.GetLogger(ctx)
+
+	var exposureResult *CheckExposureResult
+	err := workflow.ExecuteActivity(ctx, CheckExposureActivity, CheckExposureInput{
+		OrderRequest: req,
+	}).Get(ctx, &exposureResult)
+	if err != nil {
+		logger.Warn("batch order: exposure check failed", "idempotency_key", req.IdempotencyKey, "error", err)
+		return &BatchOrderOutcome{Status: BatchOrderRetryableFailure, FailureReason: fmt.Sprintf("exposure check: %v", err)}
+	}
+	if !exposureResult.Allowed {
+		logger.Warn("batch order: rejected by exposure check", "idempotency_key", req.IdempotencyKey, "reason", exposureResult.Reason)
+		return &BatchOrderOutcome{Status: BatchOrderRejected, ValidationError: exposureResult.Reason}
+	}
+
+	var reserveResult *ReserveFundsResult
+	err = workflow.ExecuteActivity(ctx, ReserveFundsActivity, ReserveFundsInput{
+		UserID:         req.UserID.String(),
+		Amount:         req.Stake.String(),
+		Currency:       req.Currency,
+		SagaID:         sagaID,
+		IdempotencyKey: req.IdempotencyKey + "-reserve",
+	}).Get(ctx, &reserveResult)
+	if err != nil {
+		logger.Warn("batch order: reserve funds failed", "idempotency_key", req.IdempotencyKey, "error", err)
+		return &BatchOrderOutcome{Status: BatchOrderRetryableFailure, FailureReason: fmt.Sprintf("reserve funds: %v", err)}
+	}
+
+	var placeResult *PlaceOrderInBookResult
+	err = workflow.ExecuteActivity(ctx, PlaceOrderInBookActivity, PlaceOrderInBookInput{
+		UserID:         req.UserID.String(),
+		EventID:        req.EventID,
+		MarketID:       req.MarketID,
+		SelectionID:    req.SelectionID,
+		Side:           string(req.Side),
+		Odds:           req.Odds.String(),
+		Stake:          req.Stake.String(),
+		Currency:       req.Currency,
+		ReservationID:  reserveResult.ReservationID,
+		SagaID:         sagaID,
+		IdempotencyKey: req.IdempotencyKey + "-order",
+	}).Get(ctx, &placeResult)
+	if err != nil {
+		logger.Warn("batch order: place in book failed", "idempotency_key", req.IdempotencyKey, "error", err)
+		cancelBatchReservation(ctx, reserveResult.ReservationID, sagaID, req.IdempotencyKey)
+		return &BatchOrderOutcome{Status: BatchOrderRetryableFailure, ReservationID: reserveResult.ReservationID, FailureReason: fmt.Sprintf("place order: %v", err)}
+	}
+
+	var commitResult *CommitReservationResult
+	err = workflow.ExecuteActivity(ctx, CommitReservationActivity, CommitReservationInput{
+		ReservationID:  reserveResult.ReservationID,
+		SagaID:         sagaID,
+		IdempotencyKey: req.IdempotencyKey + "-commit",
+	}).Get(ctx, &commitResult)
+	if err != nil {
+		logger.Warn("batch order: commit reservation failed", "idempotency_key", req.IdempotencyKey, "error", err)
+		cancelBatchOrder(ctx, placeResult.OrderID, sagaID, req.IdempotencyKey)
+		cancelBatchReservation(ctx, reserveResult.ReservationID, sagaID, req.IdempotencyKey)
+		return &BatchOrderOutcome{
+			Status:        BatchOrderRetryableFailure,
+			OrderID:       placeResult.OrderID,
+			ReservationID: reserveResult.ReservationID,
+			FailureReason: fmt.Sprintf("commit reservation: %v", err),
+		}
+	}
+
+	return &BatchOrderOutcome{
+		Status:        BatchOrderAccepted,
+		OrderID:       placeResult.OrderID,
+		ReservationID: reserveResult.ReservationID,
+		MatchID:       placeResult.MatchID,
+	}
+}
+
+// cancelBatchOrder releases an already-placed order whose reservation commit
+// failed, best-effort, without affecting other orders in the batch. It's the
+// order-side half of the compensation placeSingleOrderInBatch runs when a
+// commit fails after the order was already placed; cancelBatchReservation
+// handles the reservation-side half.
+func cancelBatchOrder(ctx workflow.Context, orderID, sagaID, idempotencyKey string) {
+	logger := workflow.GetLogger(ctx)
+
+	compensationOptions := workflow.ActivityOptions{
+		StartToCloseTimeout: 10 * time.Second,
+		RetryPolicy: &workflow.RetryPolicy{
+			InitialInterval: time.Second,
+			MaximumAttempts: 1,
+		},
+	}
+	compensationCtx := workflow.WithActivityOptions(ctx, compensationOptions)
+
+	var cancelResult *CancelOrderResult
+	err := workflow.ExecuteActivity(compensationCtx, CancelOrderActivity, CancelOrderInput{
+		OrderID:        orderID,
+		SagaID:         sagaID,
+		IdempotencyKey: idempotencyKey + "-cancel-order",
+	}).Get(compensationCtx, &cancelResult)
+	if err != nil {
+		logger.Error("batch order: unable to cancel order", "order_id", orderID, "error", err)
+	}
+}
+
+// cancelBatchReservation releases a reservation for an order that failed to
+// place, best-effort, without affecting other orders in the batch
+func cancelBatchReservation(ctx workflow.Context, reservationID, sagaID, idempotencyKey string) {
+	logger := workflow.GetLogger(ctx)
+
+	compensationOptions := workflow.ActivityOptions{
+		StartToCloseTimeout: 10 * time.Second,
+		RetryPolicy: &workflow.RetryPolicy{
+			InitialInterval: time.Second,
+			MaximumAttempts: 1,
+		},
+	}
+	compensationCtx := workflow.WithActivityOptions(ctx, compensationOptions)
+
+	var cancelResult *CancelReservationResult
+	err := workflow.ExecuteActivity(compensationCtx, CancelReservationActivity, CancelReservationInput{
+		ReservationID:  reservationID,
+		SagaID:         sagaID,
+		IdempotencyKey: idempotencyKey + "-cancel-reservation",
+	}).Get(compensationCtx, &cancelResult)
+	if err != nil {
+		logger.Error("batch order: unable to cancel reservation", "reservation_id", reservationID, "error", err)
+	}
+}
@@ -0,0 +1,88 @@
+package workflow
+
+import (
+	"fmt"
+	"time"
+
+	"go.temporal.io/sdk/workflow"
+)
+
+// CancelUserOrdersWorkflowInput is input for CancelUserOrdersWorkflow.
+// Currency and MarketID are optional filters on which of the user's live
+// orders get cancelled; empty means "every currency"/"every market".
+type CancelUserOrdersWorkflowInput struct {
+	UserID   string
+	Currency string
+	MarketID string
+	Reason   string
+}
+
+// CancelUserOrdersWorkflowResult reports a per-order and per-reservation
+// cancellation outcome. A failure in one does not roll back the other - an
+// order already cancelled alongside a reservation release failure is still
+// a cancelled order.
+type CancelUserOrdersWorkflowResult struct {
+	OrderStatuses       []*OrderCancelStatus
+	ReservationStatuses []*ReservationCancelStatus
+}
+
+// CancelUserOrdersWorkflow cancels every live order the order-book has for a
+// user, then releases any reservations the wallet still holds for them. It's
+// used for KYC/fraud freezes, market suspension, and user-initiated
+// "cancel all" requests - none of which fit the per-order PlaceOrderWorkflow
+// saga, since there's no single reservation/order pair to compensate.
+//
+// Unlike PlaceOrderWorkflow's saga, a partial failure here is not rolled
+// back: an order that's already settled or already cancelled, or a
+// reservation release that fails, is reported in the result rather than
+// undoing cancellations that already succeeded. The workflow takes no
+// special action to support cancellation - a native Temporal cancel request
+// simply interrupts whichever activity is in flight, which is enough given
+// both activities are a single bounded, idempotent operation.
+func CancelUserOrdersWorkflow(ctx workflow.Context, input CancelUserOrdersWorkflowInput) (*CancelUserOrdersWorkflowResult, error) {
+	logger := workflow.GetLogger(ctx)
+	sagaID := workflow.GetInfo(ctx).WorkflowExecution.ID
+	logger.Info("CancelUserOrdersWorkflow started", "user_id", input.UserID, "reason", input.Reason)
+
+	activityOptions := workflow.ActivityOptions{
+		StartToCloseTimeout: 30 * time.Second,
+		RetryPolicy: &workflow.RetryPolicy{
+			InitialInterval: time.Second,
+			MaximumAttempts: 3,
+		},
+	}
+	ctx = workflow.WithActivityOptions(ctx, activityOptions)
+
+	var ordersResult CancelOrdersForUserResult
+	ordersErr := workflow.ExecuteActivity(ctx, CancelOrdersForUserActivity, CancelOrdersForUserInput{
+		UserID:   input.UserID,
+		Currency: input.Currency,
+		MarketID: input.MarketID,
+		SagaID:   sagaID,
+		Reason:   input.Reason,
+	}).Get(ctx, &ordersResult)
+	if ordersErr != nil {
+		logger.Error("Failed to cancel orders for user", "error", ordersErr)
+	}
+
+	var reservationsResult CancelReservationsForUserResult
+	reservationsErr := workflow.ExecuteActivity(ctx, CancelReservationsForUserActivity, CancelReservationsForUserInput{
+		UserID:   input.UserID,
+		Currency: input.Currency,
+		MarketID: input.MarketID,
+		SagaID:   sagaID,
+		Reason:   input.Reason,
+	}).Get(ctx, &reservationsResult)
+	if reservationsErr != nil {
+		logger.Error("Failed to cancel reservations for user", "error", reservationsErr)
+	}
+
+	if ordersErr != nil && reservationsErr != nil {
+		return nil, fmt.Errorf("cancel user orders: orders: %v, reservations: %v", ordersErr, reservationsErr)
+	}
+
+	return &CancelUserOrdersWorkflowResult{
+		OrderStatuses:       ordersResult.Statuses,
+		ReservationStatuses: reservationsResult.Statuses,
+	}, nil
+}
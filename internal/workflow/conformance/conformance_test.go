@@ -0,0 +1,35 @@
+package conformance
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestVectors replays every vector under testdata/vectors against the real
+// PlaceOrderWorkflow. Run `go test ./internal/workflow/conformance/... -run
+// TestVectors/<name>` to debug a single scenario.
+//
+// Determinism: every vector's compensations are asserted to run in order via
+// ExpectedCompensations, and env.AssertExpectations fails the vector if a
+// scripted activity call never happened, so a workflow change that drops a
+// step or reorders compensations fails here; the order of the other steps is
+// not checked.
+// That's the practical signal this harness gives for "replay determinism" -
+// TestWorkflowEnvironment doesn't expose a way to record a real history and
+// replay it with worker.ReplayWorkflowHistory in the same run, so a vector
+// can't catch a nondeterminism bug that only surfaces on a real sticky-cache
+// replay (e.g. a stray time.Now() or goroutine race). That gap is for a
+// separate replay test fed from production histories, not this package.
+func TestVectors(t *testing.T) {
+	vectors, err := LoadVectors("testdata/vectors")
+	require.NoError(t, err)
+	require.NotEmpty(t, vectors, "expected at least one conformance vector under testdata/vectors")
+
+	for _, v := range vectors {
+		v := v
+		t.Run(v.Name, func(t *testing.T) {
+			RunVector(t, v)
+		})
+	}
+}
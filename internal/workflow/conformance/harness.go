@@ -0,0 +1,198 @@
+package conformance
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/testsuite"
+
+	"github.com/cypherlabdev/order-validator-service/internal/models"
+	validatorworkflow "github.com/cypherlabdev/order-validator-service/internal/workflow"
+)
+
+// outputSample returns a pointer to a zero value of the activity's real
+// output struct, so an ActivityStep.Result map can be decoded into the same
+// type PlaceOrderWorkflow itself expects back.
+func outputSample(activityName string) (interface{}, error) {
+	switch activityName {
+	case "ClaimIdempotencyKeyActivity":
+		return &validatorworkflow.ClaimIdempotencyKeyResult{}, nil
+	case "RecordIdempotencyResultActivity":
+		return &validatorworkflow.RecordIdempotencyResultResult{}, nil
+	case "GetMarketStateActivity":
+		return &validatorworkflow.GetMarketStateResult{State: &models.MarketState{}}, nil
+	case "InvalidateMarketStateActivity":
+		return &validatorworkflow.InvalidateMarketStateResult{}, nil
+	case "ValidateOrderActivity":
+		return &validatorworkflow.ValidationResult{}, nil
+	case "CheckExposureActivity":
+		return &validatorworkflow.CheckExposureResult{}, nil
+	case "ReserveFundsActivity":
+		return &validatorworkflow.ReserveFundsResult{}, nil
+	case "PlaceOrderInBookActivity":
+		return &validatorworkflow.PlaceOrderInBookResult{}, nil
+	case "RecordFillActivity":
+		return &validatorworkflow.RecordFillResult{}, nil
+	case "CommitReservationActivity":
+		return &validatorworkflow.CommitReservationResult{}, nil
+	case "CancelOrderActivity":
+		return &validatorworkflow.CancelOrderResult{}, nil
+	case "CancelReservationActivity":
+		return &validatorworkflow.CancelReservationResult{}, nil
+	case "RecordPostingActivity":
+		return &validatorworkflow.RecordPostingResult{}, nil
+	default:
+		return nil, fmt.Errorf("conformance harness: unknown activity %q - add it to outputSample", activityName)
+	}
+}
+
+// decodeResult fills a fresh outputSample(activityName) from step.Result via
+// a JSON round-trip and returns it.
+func decodeResult(activityName string, step ActivityStep) (interface{}, error) {
+	out, err := outputSample(activityName)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := json.Marshal(step.Result)
+	if err != nil {
+		return nil, fmt.Errorf("marshal %s result: %w", activityName, err)
+	}
+	if err := json.Unmarshal(raw, out); err != nil {
+		return nil, fmt.Errorf("decode %s result: %w", activityName, err)
+	}
+	return out, nil
+}
+
+// stepOutcome resolves one ActivityStep to the (result, error) pair a mocked
+// activity call should return.
+func stepOutcome(t *testing.T, activityName string, step ActivityStep) (interface{}, error) {
+	t.Helper()
+
+	switch {
+	case step.NonRetryableError != "":
+		return nil, temporal.NewNonRetryableApplicationError(step.NonRetryableError, "ConformanceVector", nil)
+	case step.Timeout:
+		// The test environment doesn't drive real StartToCloseTimeout
+		// deadlines, so a timeout vector is modeled as a plain retryable
+		// error - enough to exercise the workflow's retry/compensation
+		// logic, not Temporal's own timeout machinery.
+		return nil, errors.New("simulated timeout: " + activityName)
+	case step.Error != "":
+		return nil, errors.New(step.Error)
+	default:
+		result, err := decodeResult(activityName, step)
+		require.NoError(t, err, "decode result for %s", activityName)
+		return result, nil
+	}
+}
+
+// toOrderRequest converts a vector's OrderRequestVector into the same
+// models.PlaceOrderRequest PlaceOrderWorkflow is started with
+func toOrderRequest(v OrderRequestVector) (*models.PlaceOrderRequest, error) {
+	userID, err := uuid.Parse(v.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("parse user_id: %w", err)
+	}
+	odds, err := decimal.NewFromString(v.Odds)
+	if err != nil {
+		return nil, fmt.Errorf("parse odds: %w", err)
+	}
+	stake, err := decimal.NewFromString(v.Stake)
+	if err != nil {
+		return nil, fmt.Errorf("parse stake: %w", err)
+	}
+
+	return &models.PlaceOrderRequest{
+		UserID:         userID,
+		EventID:        v.EventID,
+		MarketID:       v.MarketID,
+		SelectionID:    v.SelectionID,
+		Side:           models.OrderSide(v.Side),
+		Odds:           odds,
+		Stake:          stake,
+		Currency:       v.Currency,
+		IdempotencyKey: v.IdempotencyKey,
+	}, nil
+}
+
+// compensationNames is the set of activities that only ever run as part of
+// compensate(), used to filter the full call trace down to compensations
+var compensationNames = map[string]bool{
+	"CancelOrderActivity":       true,
+	"CancelReservationActivity": true,
+}
+
+func compensationsOnly(executed []string) []string {
+	out := make([]string, 0, len(executed))
+	for _, name := range executed {
+		if compensationNames[name] {
+			out = append(out, name)
+		}
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+// RunVector replays v against the real PlaceOrderWorkflow under a Temporal
+// test environment, mocking every activity call per v.Activities, and
+// asserts the saga's final status, the compensations it ran (in order), and
+// - for a vector with no expected compensations - that none ran at all.
+func RunVector(t *testing.T, v *Vector) {
+	t.Helper()
+
+	orderRequest, err := toOrderRequest(v.OrderRequest)
+	require.NoError(t, err, "vector %s: invalid order_request", v.Name)
+
+	var suite testsuite.WorkflowTestSuite
+	env := suite.NewTestWorkflowEnvironment()
+
+	var mu sync.Mutex
+	var executed []string
+
+	for name, steps := range v.Activities {
+		name := name
+		for _, step := range steps {
+			result, retErr := stepOutcome(t, name, step)
+			// One expectation per step: each call is answered by the first
+			// expectation that still has a use left, so steps play in order
+			env.OnActivity(name, mock.Anything, mock.Anything).Run(func(mock.Arguments) {
+				mu.Lock()
+				executed = append(executed, name)
+				mu.Unlock()
+			}).Return(result, retErr).Once()
+		}
+	}
+
+	env.ExecuteWorkflow(validatorworkflow.PlaceOrderWorkflow, validatorworkflow.PlaceOrderWorkflowInput{
+		OrderRequest: orderRequest,
+		SagaID:       "conformance-" + v.Name,
+	})
+
+	require.True(t, env.IsWorkflowCompleted(), "vector %s: workflow did not complete", v.Name)
+
+	var result validatorworkflow.PlaceOrderWorkflowResult
+	_ = env.GetWorkflowResult(&result) // a terminal saga failure is an expected outcome, not a harness error
+
+	assert.Equal(t, v.Expected.Status, string(result.Status), "vector %s: final status", v.Name)
+	if v.Expected.FailureReasonContains != "" {
+		assert.Contains(t, result.FailureReason, v.Expected.FailureReasonContains, "vector %s: failure reason", v.Name)
+	}
+
+	mu.Lock()
+	assert.Equal(t, v.ExpectedCompensations, compensationsOnly(executed), "vector %s: compensations executed, in order", v.Name)
+	mu.Unlock()
+
+	env.AssertExpectations(t)
+}
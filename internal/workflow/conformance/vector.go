@@ -0,0 +1,125 @@
+// Package conformance loads versioned test vectors describing a
+// PlaceOrderWorkflow run - a scripted sequence of activity outcomes, plus
+// the saga result and compensations that run is expected to produce - and
+// replays each one against the real workflow code under Temporal's test
+// environment. It exists so wallet-service and order-book can contribute a
+// vector proving their gRPC contract still drives the saga the way this
+// service expects, without needing a live integration environment.
+package conformance
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// VectorVersion is the current vector schema version. Bump it, and extend
+// Vector.Version handling in LoadVector, if a field is added or changed in a
+// way that breaks older vectors.
+const VectorVersion = 1
+
+// Vector describes one PlaceOrderWorkflow run: the order submitted, the
+// scripted response for every activity call the saga makes, and what the
+// saga is expected to do in response.
+type Vector struct {
+	Version     int    `yaml:"version"`
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+
+	OrderRequest OrderRequestVector `yaml:"order_request"`
+
+	// Activities maps an activity name (e.g. "ReserveFundsActivity") to the
+	// ordered sequence of outcomes it returns, one per call. A flaky
+	// activity that fails once and succeeds on retry is two ActivitySteps;
+	// an activity called only once is a single-element list.
+	Activities map[string][]ActivityStep `yaml:"activities"`
+
+	Expected              ExpectedResult `yaml:"expected"`
+	ExpectedCompensations []string       `yaml:"expected_compensations"`
+}
+
+// OrderRequestVector is the YAML-friendly form of models.PlaceOrderRequest
+type OrderRequestVector struct {
+	UserID         string `yaml:"user_id"`
+	EventID        string `yaml:"event_id"`
+	MarketID       string `yaml:"market_id"`
+	SelectionID    string `yaml:"selection_id"`
+	Side           string `yaml:"side"`
+	Odds           string `yaml:"odds"`
+	Stake          string `yaml:"stake"`
+	Currency       string `yaml:"currency"`
+	IdempotencyKey string `yaml:"idempotency_key"`
+}
+
+// ActivityStep is one scripted outcome for one call to an activity.
+// Exactly one of Result, Error, or NonRetryableError should be set.
+type ActivityStep struct {
+	// Result is decoded into the activity's real output struct via a JSON
+	// round-trip, so a key is a field's json tag where it has one (e.g.
+	// "mark_price" for MarketState.MarkPrice) and otherwise its field name,
+	// matched case-insensitively (e.g. "valid", "reason" for ValidationResult).
+	Result map[string]interface{} `yaml:"result"`
+
+	// Error fails the call with a plain error, which PlaceOrderWorkflow's
+	// ActivityOptions.RetryPolicy will retry like any transient failure
+	// (e.g. a flaky response that succeeds on a later ActivityStep).
+	Error string `yaml:"error"`
+
+	// NonRetryableError fails the call with a Temporal non-retryable
+	// application error - e.g. a validation rejection from the downstream
+	// service that retrying would never fix.
+	NonRetryableError string `yaml:"non_retryable_error"`
+
+	// Timeout models a StartToCloseTimeout expiry. The test environment
+	// doesn't drive real activity deadlines, so this is modeled as a plain
+	// retryable error with a recognizable message rather than an actual
+	// timeout - good enough to exercise the workflow's retry/compensation
+	// logic, not a test of Temporal's own timeout machinery.
+	Timeout bool `yaml:"timeout"`
+}
+
+// ExpectedResult is the subset of PlaceOrderWorkflowResult a vector asserts
+type ExpectedResult struct {
+	Status                string `yaml:"status"`
+	FailureReasonContains string `yaml:"failure_reason_contains"`
+}
+
+// LoadVector reads and parses a single vector file
+func LoadVector(path string) (*Vector, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read vector %s: %w", path, err)
+	}
+
+	var v Vector
+	if err := yaml.Unmarshal(data, &v); err != nil {
+		return nil, fmt.Errorf("parse vector %s: %w", path, err)
+	}
+
+	if v.Version != VectorVersion {
+		return nil, fmt.Errorf("vector %s: unsupported version %d (harness supports %d)", path, v.Version, VectorVersion)
+	}
+
+	return &v, nil
+}
+
+// LoadVectors reads every *.yaml vector under dir
+func LoadVectors(dir string) ([]*Vector, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.yaml"))
+	if err != nil {
+		return nil, fmt.Errorf("glob vectors dir %s: %w", dir, err)
+	}
+
+	vectors := make([]*Vector, 0, len(paths))
+	for _, path := range paths {
+		v, err := LoadVector(path)
+		if err != nil {
+			return nil, err
+		}
+		vectors = append(vectors, v)
+	}
+
+	return vectors, nil
+}
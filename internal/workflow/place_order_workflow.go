@@ -1,11 +1,14 @@
 package workflow
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
 	"go.temporal.io/sdk/workflow"
 
+	"github.com/cypherlabdev/order-validator-service/internal/ledger"
 	"github.com/cypherlabdev/order-validator-service/internal/models"
 )
 
@@ -60,17 +63,166 @@ func PlaceOrderWorkflow(ctx workflow.Context, input PlaceOrderWorkflowInput) (*P
 	var reservationID string
 	var orderID string
 
+	// sm is the single source of truth for the saga's current stage. It's
+	// exposed to the outside world via the saga_state query handler so
+	// RecoverSaga can inspect a saga. Only the workflow's own steps and
+	// compensate() move it; the resume_saga/compensate_saga handlers below
+	// just log the signal.
+	sm := NewSagaStateMachine()
+	if err := workflow.SetQueryHandler(ctx, SagaStateQueryType, func() (SagaState, error) {
+		return sm.State(), nil
+	}); err != nil {
+		return result, fmt.Errorf("register saga_state query handler: %w", err)
+	}
+
+	// postings tracks every RecordPostingActivity call this run has made, so
+	// the ledger_postings query can answer for a still-running saga without
+	// a blocking activity call of its own; GetSagaPostingsActivity is the
+	// durable equivalent once the saga has completed.
+	var postings []*PostingSummary
+	if err := workflow.SetQueryHandler(ctx, LedgerPostingsQueryType, func() ([]*PostingSummary, error) {
+		return postings, nil
+	}); err != nil {
+		return result, fmt.Errorf("register ledger_postings query handler: %w", err)
+	}
+
+	resumeSagaCh := workflow.GetSignalChannel(ctx, ResumeSagaSignalName)
+	workflow.Go(ctx, func(gCtx workflow.Context) {
+		for {
+			if !resumeSagaCh.Receive(gCtx, nil) {
+				return
+			}
+			// PlaceOrderWorkflow's steps already retry and time out on their
+			// own via ActivityOptions.RetryPolicy, so there's nothing extra
+			// to kick off here; this just gives RecoverSaga something to
+			// signal for an audit trail of recovery attempts.
+			logger.Info("Received resume_saga signal", "saga_state", sm.State())
+		}
+	})
+
+	compensateSagaCh := workflow.GetSignalChannel(ctx, CompensateSagaSignalName)
+	workflow.Go(ctx, func(gCtx workflow.Context) {
+		for {
+			if !compensateSagaCh.Receive(gCtx, nil) {
+				return
+			}
+			logger.Info("Received compensate_saga signal", "saga_state", sm.State())
+		}
+	})
+
+	// Step -1: Claim the idempotency key before doing any other work. A
+	// duplicate submission either replays the cached terminal result or, if
+	// another workflow is still processing the same key, fails fast.
+	logger.Info("Step -1: Claiming idempotency key", "idempotency_key", input.OrderRequest.IdempotencyKey)
+	var claimResult *ClaimIdempotencyKeyResult
+	err := workflow.ExecuteActivity(ctx, ClaimIdempotencyKeyActivity, ClaimIdempotencyKeyInput{
+		UserID:         input.OrderRequest.UserID.String(),
+		IdempotencyKey: input.OrderRequest.IdempotencyKey,
+		WorkflowID:     workflow.GetInfo(ctx).WorkflowExecution.ID,
+	}).Get(ctx, &claimResult)
+
+	if err != nil {
+		logger.Error("Failed to claim idempotency key", "error", err)
+		result.Status = models.OrderStatusFailed
+		result.FailureReason = fmt.Sprintf("idempotency claim failed: %v", err)
+		return result, err
+	}
+
+	if claimResult.InProgress {
+		logger.Warn("Idempotency key already in progress under another workflow", "existing_workflow_id", claimResult.WorkflowID)
+		result.Status = models.OrderStatusFailed
+		result.FailureReason = models.ErrIdempotencyKeyInProgress
+		return result, errors.New(models.ErrIdempotencyKeyInProgress)
+	}
+
+	if !claimResult.FirstSeen {
+		logger.Info("Idempotency key already resolved, replaying cached result", "existing_workflow_id", claimResult.WorkflowID)
+		var cached PlaceOrderWorkflowResult
+		if err := json.Unmarshal([]byte(claimResult.FinalResult), &cached); err != nil {
+			return result, fmt.Errorf("decode cached idempotency result: %w", err)
+		}
+		return &cached, nil
+	}
+
+	// Record the saga's terminal outcome under the idempotency key no matter
+	// which path the workflow exits through, so a retried submission of the
+	// same key can be answered from cache instead of re-running the saga.
+	defer func() {
+		finalResultJSON, marshalErr := json.Marshal(result)
+		if marshalErr != nil {
+			logger.Error("Failed to marshal final result for idempotency record", "error", marshalErr)
+			return
+		}
+
+		recordCtx := workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+			StartToCloseTimeout: 5 * time.Second,
+			RetryPolicy:         &workflow.RetryPolicy{MaximumAttempts: 3},
+		})
+		_ = workflow.ExecuteActivity(recordCtx, RecordIdempotencyResultActivity, RecordIdempotencyResultInput{
+			UserID:         input.OrderRequest.UserID.String(),
+			IdempotencyKey: input.OrderRequest.IdempotencyKey,
+			Success:        result.Status == models.OrderStatusSettled,
+			FinalResult:    string(finalResultJSON),
+		}).Get(recordCtx, nil)
+	}()
+
+	// Listen for out-of-band market state invalidation signals (e.g. sent by
+	// a market-data watcher when status/odds change) and evict the cached
+	// entry so the next GetMarketState call re-fetches from upstream.
+	invalidateSignalCh := workflow.GetSignalChannel(ctx, "invalidate_market_state")
+	workflow.Go(ctx, func(gCtx workflow.Context) {
+		for {
+			var marketID string
+			if !invalidateSignalCh.Receive(gCtx, &marketID) {
+				return
+			}
+			invalidateCtx := workflow.WithActivityOptions(gCtx, workflow.ActivityOptions{
+				StartToCloseTimeout: 5 * time.Second,
+				RetryPolicy:         &workflow.RetryPolicy{MaximumAttempts: 1},
+			})
+			_ = workflow.ExecuteActivity(invalidateCtx, InvalidateMarketStateActivity, InvalidateMarketStateInput{
+				MarketID: marketID,
+			}).Get(invalidateCtx, nil)
+		}
+	})
+
+	// Step 0: Check market state - reject outright if the market isn't open
+	logger.Info("Step 0: Checking market state", "market_id", input.OrderRequest.MarketID)
+	var marketStateResult *GetMarketStateResult
+	err = workflow.ExecuteActivity(ctx, GetMarketStateActivity, GetMarketStateInput{
+		MarketID: input.OrderRequest.MarketID,
+	}).Get(ctx, &marketStateResult)
+
+	if err != nil {
+		logger.Error("Failed to fetch market state", "error", err)
+		result.Status = models.OrderStatusFailed
+		result.FailureReason = fmt.Sprintf("market state lookup failed: %v", err)
+		_ = sm.Transition(SagaStateTerminalFailed)
+		return result, err
+	}
+
+	if marketStateResult.State.Status != models.MarketStatusOpen {
+		logger.Warn("Market not open for betting", "market_id", input.OrderRequest.MarketID, "status", marketStateResult.State.Status)
+		result.Status = models.OrderStatusFailed
+		result.FailureReason = models.ErrMarketClosed
+		_ = sm.Transition(SagaStateTerminalFailed)
+		return result, errors.New(models.ErrMarketClosed)
+	}
+
 	// Step 1: Validate Order
 	logger.Info("Step 1: Validating order")
 	var validationResult *ValidationResult
-	err := workflow.ExecuteActivity(ctx, ValidateOrderActivity, ValidateOrderInput{
+	err = workflow.ExecuteActivity(ctx, ValidateOrderActivity, ValidateOrderInput{
 		OrderRequest: input.OrderRequest,
+		MarkPrice:    marketStateResult.State.MarkPrice,
+		SagaID:       input.SagaID,
 	}).Get(ctx, &validationResult)
 
 	if err != nil {
 		logger.Error("Order validation failed", "error", err)
 		result.Status = models.OrderStatusFailed
 		result.FailureReason = fmt.Sprintf("validation failed: %v", err)
+		_ = sm.Transition(SagaStateTerminalFailed)
 		return result, err
 	}
 
@@ -78,10 +230,37 @@ func PlaceOrderWorkflow(ctx workflow.Context, input PlaceOrderWorkflowInput) (*P
 		logger.Warn("Order validation rejected", "reason", validationResult.Reason)
 		result.Status = models.OrderStatusFailed
 		result.FailureReason = validationResult.Reason
+		_ = sm.Transition(SagaStateTerminalFailed)
 		return result, fmt.Errorf("validation failed: %s", validationResult.Reason)
 	}
 
 	logger.Info("Order validation passed")
+	if err := sm.Transition(SagaStateValidated); err != nil {
+		logger.Error("Unexpected saga state transition error", "error", err)
+	}
+
+	// Step 1b: Check per-user exposure limits before committing any funds
+	logger.Info("Step 1b: Checking exposure limits")
+	var exposureResult *CheckExposureResult
+	err = workflow.ExecuteActivity(ctx, CheckExposureActivity, CheckExposureInput{
+		OrderRequest: input.OrderRequest,
+	}).Get(ctx, &exposureResult)
+
+	if err != nil {
+		logger.Error("Exposure check failed", "error", err)
+		result.Status = models.OrderStatusFailed
+		result.FailureReason = fmt.Sprintf("exposure check failed: %v", err)
+		_ = sm.Transition(SagaStateTerminalFailed)
+		return result, err
+	}
+
+	if !exposureResult.Allowed {
+		logger.Warn("Order rejected by exposure check", "reason", exposureResult.Reason)
+		result.Status = models.OrderStatusFailed
+		result.FailureReason = exposureResult.Reason
+		_ = sm.Transition(SagaStateTerminalFailed)
+		return result, fmt.Errorf("exposure check failed: %s", exposureResult.Reason)
+	}
 
 	// Step 2: Reserve Funds in Wallet
 	logger.Info("Step 2: Reserving funds", "amount", input.OrderRequest.Stake)
@@ -100,17 +279,33 @@ func PlaceOrderWorkflow(ctx workflow.Context, input PlaceOrderWorkflowInput) (*P
 		logger.Error("Failed to reserve funds", "error", err)
 		result.Status = models.OrderStatusFailed
 		result.FailureReason = fmt.Sprintf("fund reservation failed: %v", err)
+		_ = sm.Transition(SagaStateTerminalFailed)
 		return result, err
 	}
 
 	reservationID = reserveResult.ReservationID
 	result.ReservationID = reservationID
 	logger.Info("Funds reserved successfully", "reservation_id", reservationID)
+	if err := sm.Transition(SagaStateFundsReserved); err != nil {
+		logger.Error("Unexpected saga state transition error", "error", err)
+	}
+
+	// Posted after ReserveFunds has already completed, not before - a posting
+	// that failed to record on a call that never actually reserved funds
+	// would silently invent ledger drift.
+	recordPosting(ctx, &postings, input.SagaID, input.OrderRequest.IdempotencyKey+"-posting-reserve",
+		ledger.UserAvailableAccount(input.OrderRequest.UserID.String(), input.OrderRequest.Currency),
+		ledger.UserReservedAccount(input.OrderRequest.UserID.String(), input.OrderRequest.Currency),
+		input.OrderRequest.Stake.String(), input.OrderRequest.Currency, "reserve", reservationID, logger)
+
+	userID := input.OrderRequest.UserID.String()
+	currency := input.OrderRequest.Currency
+	stake := input.OrderRequest.Stake.String()
 
 	// From here on, we must compensate on failure
 	defer func() {
 		if result.Status == models.OrderStatusFailed || result.Status == models.OrderStatusCancelled {
-			compensate(ctx, reservationID, orderID, input.SagaID, logger)
+			compensate(ctx, sm, reservationID, orderID, input.SagaID, userID, currency, stake, &postings, logger)
 		}
 	}()
 
@@ -143,6 +338,33 @@ func PlaceOrderWorkflow(ctx workflow.Context, input PlaceOrderWorkflowInput) (*P
 	orderID = placeOrderResult.OrderID
 	result.OrderID = orderID
 	logger.Info("Order placed in book", "order_id", orderID, "status", placeOrderResult.Status)
+	if err := sm.Transition(SagaStateOrderPlaced); err != nil {
+		logger.Error("Unexpected saga state transition error", "error", err)
+	}
+
+	// Step 3b: Record the fill. The order book may report a match immediately
+	// on placement, so this runs as soon as a match could exist rather than
+	// waiting for the saga to reach its terminal state. Recording failures
+	// don't fail the saga - the match already happened in the order book, and
+	// a missed fill row is a settlement-reconciliation concern, not a reason
+	// to unwind funds that have already been committed to a real match.
+	if placeOrderResult.Status == "MATCHED" || placeOrderResult.Status == "PARTIALLY_FILLED" {
+		logger.Info("Step 3b: Recording fill", "order_id", orderID, "match_id", placeOrderResult.MatchID)
+		var recordFillResult *RecordFillResult
+		fillErr := workflow.ExecuteActivity(ctx, RecordFillActivity, RecordFillInput{
+			OrderID:      orderID,
+			SagaID:       input.SagaID,
+			UserID:       input.OrderRequest.UserID.String(),
+			MatchID:      placeOrderResult.MatchID,
+			Side:         string(input.OrderRequest.Side),
+			MatchedOdds:  input.OrderRequest.Odds.String(),
+			MatchedStake: input.OrderRequest.Stake.String(),
+		}).Get(ctx, &recordFillResult)
+
+		if fillErr != nil {
+			logger.Error("Failed to record fill", "order_id", orderID, "error", fillErr)
+		}
+	}
 
 	// Step 4: Commit Reservation (funds are now committed)
 	logger.Info("Step 4: Committing reservation")
@@ -162,10 +384,20 @@ func PlaceOrderWorkflow(ctx workflow.Context, input PlaceOrderWorkflowInput) (*P
 	}
 
 	logger.Info("Reservation committed successfully")
+	if err := sm.Transition(SagaStateMatched); err != nil {
+		logger.Error("Unexpected saga state transition error", "error", err)
+	}
+
+	recordPosting(ctx, &postings, input.SagaID, input.OrderRequest.IdempotencyKey+"-posting-commit",
+		ledger.UserReservedAccount(userID, currency), ledger.HousePnLPendingAccount(currency),
+		stake, currency, "commit", reservationID, logger)
 
 	// Success!
 	result.Status = models.OrderStatusSettled
 	result.MatchID = placeOrderResult.MatchID
+	if err := sm.Transition(SagaStateTerminalSuccess); err != nil {
+		logger.Error("Unexpected saga state transition error", "error", err)
+	}
 
 	logger.Info("PlaceOrderWorkflow completed successfully",
 		"order_id", orderID,
@@ -175,8 +407,11 @@ func PlaceOrderWorkflow(ctx workflow.Context, input PlaceOrderWorkflowInput) (*P
 	return result, nil
 }
 
-// compensate performs compensation actions when the saga fails
-func compensate(ctx workflow.Context, reservationID, orderID, sagaID string, logger workflow.Logger) {
+// compensate performs compensation actions when the saga fails, driving sm
+// through CompensatingOrder/CompensatingReserve on the way to a terminal
+// state so a concurrent saga_state query always reflects what's actually
+// happening rather than lagging behind as "FUNDS_RESERVED" or "ORDER_PLACED".
+func compensate(ctx workflow.Context, sm *SagaStateMachine, reservationID, orderID, sagaID, userID, currency, stake string, postings *[]*PostingSummary, logger workflow.Logger) {
 	logger.Warn("Saga failed, starting compensation")
 
 	// Configure compensation activity options (no retries, best effort)
@@ -193,6 +428,10 @@ func compensate(ctx workflow.Context, reservationID, orderID, sagaID string, log
 
 	// 1. Cancel order in order-book (if placed)
 	if orderID != "" {
+		if err := sm.Transition(SagaStateCompensatingOrder); err != nil {
+			logger.Error("Unexpected saga state transition error", "error", err)
+		}
+
 		logger.Info("Compensating: Cancelling order", "order_id", orderID)
 		var cancelOrderResult *CancelOrderResult
 		err := workflow.ExecuteActivity(compensationCtx, CancelOrderActivity, CancelOrderInput{
@@ -211,6 +450,10 @@ func compensate(ctx workflow.Context, reservationID, orderID, sagaID string, log
 
 	// 2. Cancel reservation in wallet (if reserved)
 	if reservationID != "" {
+		if err := sm.Transition(SagaStateCompensatingReserve); err != nil {
+			logger.Error("Unexpected saga state transition error", "error", err)
+		}
+
 		logger.Info("Compensating: Cancelling reservation", "reservation_id", reservationID)
 		var cancelReservationResult *CancelReservationResult
 		err := workflow.ExecuteActivity(compensationCtx, CancelReservationActivity, CancelReservationInput{
@@ -224,8 +467,60 @@ func compensate(ctx workflow.Context, reservationID, orderID, sagaID string, log
 			// This is critical - should alert operations
 		} else {
 			logger.Info("Reservation cancelled successfully", "reservation_id", reservationID)
+
+			// Posted after CancelReservation has already completed, reversing
+			// the reserve posting now that the funds are back in the user's
+			// available balance.
+			recordPosting(ctx, postings, sagaID, sagaID+"-posting-cancel-reservation",
+				ledger.UserReservedAccount(userID, currency), ledger.UserAvailableAccount(userID, currency),
+				stake, currency, "cancel_reservation", reservationID, logger)
+		}
+	}
+
+	if sm.State() == SagaStateCompensatingReserve {
+		if err := sm.Transition(SagaStateTerminalFailed); err != nil {
+			logger.Error("Unexpected saga state transition error", "error", err)
 		}
 	}
 
 	logger.Info("Compensation completed")
 }
+
+// recordPosting calls RecordPostingActivity and, on success, appends a
+// PostingSummary to *postings for the ledger_postings query. A posting
+// failure is logged and swallowed rather than failing the saga - the wallet
+// RPC it's recording already completed, and the ledger is an auxiliary audit
+// trail, not a system the saga's own correctness depends on.
+func recordPosting(ctx workflow.Context, postings *[]*PostingSummary, sagaID, idempotencyKey, debitAccount, creditAccount, amount, currency, reason, causalRef string, logger workflow.Logger) {
+	postingCtx := workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		StartToCloseTimeout: 10 * time.Second,
+		RetryPolicy:         &workflow.RetryPolicy{InitialInterval: time.Second, MaximumAttempts: 3},
+	})
+
+	var result *RecordPostingResult
+	err := workflow.ExecuteActivity(postingCtx, RecordPostingActivity, RecordPostingInput{
+		SagaID:         sagaID,
+		IdempotencyKey: idempotencyKey,
+		DebitAccount:   debitAccount,
+		CreditAccount:  creditAccount,
+		Amount:         amount,
+		Currency:       currency,
+		Reason:         reason,
+		CausalRef:      causalRef,
+	}).Get(postingCtx, &result)
+
+	if err != nil {
+		logger.Warn("Failed to record ledger posting", "reason", reason, "debit_account", debitAccount, "credit_account", creditAccount, "error", err)
+		return
+	}
+
+	*postings = append(*postings, &PostingSummary{
+		PostingID:     result.PostingID,
+		DebitAccount:  debitAccount,
+		CreditAccount: creditAccount,
+		Amount:        amount,
+		Currency:      currency,
+		Reason:        reason,
+		CausalRef:     causalRef,
+	})
+}
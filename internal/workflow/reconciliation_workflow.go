@@ -0,0 +1,83 @@
+package workflow
+
+import (
+	"fmt"
+	"time"
+
+	"go.temporal.io/sdk/workflow"
+)
+
+// ReconciliationReportQueryType is the query handler name ReconciliationWorkflow
+// registers for reading its most recently completed reconciliation pass.
+const ReconciliationReportQueryType = "reconciliation_report"
+
+// ReconciliationWorkflowInput is the input for ReconciliationWorkflow
+type ReconciliationWorkflowInput struct {
+	// UserIDs is the set of accounts to check this pass. In practice a
+	// caller shards this across several workflow runs rather than checking
+	// the whole user base from one.
+	UserIDs  []string
+	Currency string
+	// Interval is how long the workflow sleeps between passes
+	Interval time.Duration
+}
+
+// ReconciliationReport is the result of one ReconciliationWorkflow pass
+type ReconciliationReport struct {
+	Drifts    []*AccountDrift
+	CheckedAt string // RFC3339, set from workflow.Now so it's deterministic on replay
+}
+
+// ReconciliationWorkflow periodically compares the ledger's projected
+// balances against wallet-service's own authoritative balances for every
+// user in input.UserIDs, keeping the latest ReconciliationReport available
+// via the reconciliation_report query. It re-runs itself with ContinueAsNew
+// after each pass rather than relying on an external scheduler, so a single
+// workflow ID's event history never grows unbounded across what's meant to
+// run indefinitely.
+func ReconciliationWorkflow(ctx workflow.Context, input ReconciliationWorkflowInput) error {
+	logger := workflow.GetLogger(ctx)
+	logger.Info("ReconciliationWorkflow pass started", "user_count", len(input.UserIDs), "currency", input.Currency)
+
+	report := &ReconciliationReport{}
+	if err := workflow.SetQueryHandler(ctx, ReconciliationReportQueryType, func() (*ReconciliationReport, error) {
+		return report, nil
+	}); err != nil {
+		return fmt.Errorf("register reconciliation_report query handler: %w", err)
+	}
+
+	activityOptions := workflow.ActivityOptions{
+		StartToCloseTimeout: 30 * time.Second,
+		RetryPolicy:         &workflow.RetryPolicy{InitialInterval: time.Second, MaximumAttempts: 3},
+	}
+	ctx = workflow.WithActivityOptions(ctx, activityOptions)
+
+	for _, userID := range input.UserIDs {
+		var result *ReconcileWithWalletResult
+		err := workflow.ExecuteActivity(ctx, ReconcileWithWalletActivity, ReconcileWithWalletInput{
+			UserID:   userID,
+			Currency: input.Currency,
+		}).Get(ctx, &result)
+
+		if err != nil {
+			logger.Warn("Reconciliation check failed for user, skipping this pass", "user_id", userID, "error", err)
+			continue
+		}
+
+		report.Drifts = append(report.Drifts, result.Drifts...)
+	}
+
+	report.CheckedAt = workflow.Now(ctx).UTC().Format(time.RFC3339)
+
+	if len(report.Drifts) > 0 {
+		logger.Warn("ReconciliationWorkflow pass found drift", "drift_count", len(report.Drifts))
+	} else {
+		logger.Info("ReconciliationWorkflow pass found no drift", "users_checked", len(input.UserIDs))
+	}
+
+	if err := workflow.Sleep(ctx, input.Interval); err != nil {
+		return fmt.Errorf("reconciliation interval sleep: %w", err)
+	}
+
+	return workflow.NewContinueAsNewError(ctx, ReconciliationWorkflow, input)
+}
@@ -0,0 +1,120 @@
+package workflow
+
+import "fmt"
+
+// SagaState is one stage of a PlaceOrderWorkflow saga.
+type SagaState string
+
+const (
+	SagaStatePending             SagaState = "PENDING"
+	SagaStateValidated           SagaState = "VALIDATED"
+	SagaStateFundsReserved       SagaState = "FUNDS_RESERVED"
+	SagaStateOrderPlaced         SagaState = "ORDER_PLACED"
+	SagaStateMatched             SagaState = "MATCHED"
+	SagaStateCompensatingReserve SagaState = "COMPENSATING_RESERVE"
+	SagaStateCompensatingOrder   SagaState = "COMPENSATING_ORDER"
+	SagaStateTerminalSuccess     SagaState = "TERMINAL_SUCCESS"
+	SagaStateTerminalFailed      SagaState = "TERMINAL_FAILED"
+	SagaStateTerminalCancelled   SagaState = "TERMINAL_CANCELLED"
+)
+
+// SagaStateQueryType is the query handler name PlaceOrderWorkflow registers
+// so RecoverSaga can read a running saga's current state.
+const SagaStateQueryType = "saga_state"
+
+// ResumeSagaSignalName asks a stuck-but-recoverable saga to continue
+// forward. PlaceOrderWorkflow's activities already retry and time out on
+// their own, so in practice this just gives an operator (via RecoverSaga) a
+// way to record that recovery was attempted; the activity itself does the
+// work the next time it's scheduled.
+const ResumeSagaSignalName = "resume_saga"
+
+// CompensateSagaSignalName is the signal RecoverSaga sends to a saga that has
+// crossed its point-of-no-return. PlaceOrderWorkflow only logs it: receiving
+// it does not start compensation or stop the saga's current step.
+const CompensateSagaSignalName = "compensate_saga"
+
+// LedgerPostingsQueryType is the query handler name PlaceOrderWorkflow
+// registers for reading the double-entry postings it has recorded so far.
+const LedgerPostingsQueryType = "ledger_postings"
+
+// sagaTransitions lists which SagaState may follow which. PlaceOrderWorkflow
+// moves through it via SagaStateMachine, which rejects any transition not
+// listed here; the order of the saga's steps is written out in
+// PlaceOrderWorkflow's own control flow, so a new step needs workflow code as
+// well as a table entry. Recover does not read this table: it decides with
+// IsTerminal and PastPointOfNoReturn.
+var sagaTransitions = map[SagaState][]SagaState{
+	SagaStatePending:             {SagaStateValidated, SagaStateTerminalFailed},
+	SagaStateValidated:           {SagaStateFundsReserved, SagaStateTerminalFailed},
+	SagaStateFundsReserved:       {SagaStateOrderPlaced, SagaStateCompensatingReserve},
+	SagaStateOrderPlaced:         {SagaStateMatched, SagaStateCompensatingOrder},
+	SagaStateMatched:             {SagaStateTerminalSuccess},
+	SagaStateCompensatingOrder:   {SagaStateCompensatingReserve},
+	SagaStateCompensatingReserve: {SagaStateTerminalFailed, SagaStateTerminalCancelled},
+}
+
+// IsTerminal reports whether no further transitions are possible from s.
+func (s SagaState) IsTerminal() bool {
+	switch s {
+	case SagaStateTerminalSuccess, SagaStateTerminalFailed, SagaStateTerminalCancelled:
+		return true
+	default:
+		return false
+	}
+}
+
+// PastPointOfNoReturn reports whether s is at or beyond FundsReserved, i.e.
+// real funds are on hold and unwinding the saga now requires compensation
+// rather than simply letting it continue or abandoning it outright.
+func (s SagaState) PastPointOfNoReturn() bool {
+	switch s {
+	case SagaStateFundsReserved, SagaStateOrderPlaced, SagaStateMatched,
+		SagaStateCompensatingReserve, SagaStateCompensatingOrder:
+		return true
+	default:
+		return false
+	}
+}
+
+// NextStates returns the states reachable from from, for callers that need
+// to reason about a saga without driving it through a live SagaStateMachine.
+func NextStates(from SagaState) []SagaState {
+	return sagaTransitions[from]
+}
+
+// SagaStateMachine tracks one saga's current state and rejects any
+// transition not listed in sagaTransitions.
+type SagaStateMachine struct {
+	current SagaState
+}
+
+// NewSagaStateMachine creates a state machine starting at SagaStatePending.
+func NewSagaStateMachine() *SagaStateMachine {
+	return &SagaStateMachine{current: SagaStatePending}
+}
+
+// State returns the current state.
+func (m *SagaStateMachine) State() SagaState {
+	return m.current
+}
+
+// CanTransition reports whether moving from the current state to to is legal.
+func (m *SagaStateMachine) CanTransition(to SagaState) bool {
+	for _, allowed := range sagaTransitions[m.current] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Transition moves the state machine to to, or returns an error if that
+// transition isn't present in sagaTransitions for the current state.
+func (m *SagaStateMachine) Transition(to SagaState) error {
+	if !m.CanTransition(to) {
+		return fmt.Errorf("saga state machine: illegal transition from %s to %s", m.current, to)
+	}
+	m.current = to
+	return nil
+}
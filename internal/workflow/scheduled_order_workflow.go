@@ -0,0 +1,355 @@
+package workflow
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/cypherlabdev/order-validator-service/internal/models"
+)
+
+// ScheduledOrderState is one stage of a ScheduledOrderWorkflow run, upserted
+// as a workflow search attribute (see scheduledOrderStateSearchAttribute) so
+// an operator can filter running DCA strategies by stage via Temporal's
+// visibility API without having to query every workflow individually.
+type ScheduledOrderState string
+
+const (
+	ScheduledOrderStateIdle          ScheduledOrderState = "IDLE"
+	ScheduledOrderStateOpening       ScheduledOrderState = "OPENING"
+	ScheduledOrderStateRunning       ScheduledOrderState = "RUNNING"
+	ScheduledOrderStateTakingProfit  ScheduledOrderState = "TAKING_PROFIT"
+	ScheduledOrderStateCircuitBroken ScheduledOrderState = "CIRCUIT_BROKEN"
+	ScheduledOrderStateStopped       ScheduledOrderState = "STOPPED"
+)
+
+// scheduledOrderStateSearchAttribute is the search attribute key
+// ScheduledOrderWorkflow upserts on every state transition. It must be
+// registered with Temporal's visibility store as a Keyword before it's
+// usable in a List filter.
+const scheduledOrderStateSearchAttribute = "ScheduledOrderState"
+
+// QueryCumulativePnLType is the query handler name ScheduledOrderWorkflow
+// registers for reading its running PnL total: realized PnL of settled legs
+// plus their mark-to-market unrealized PnL.
+const QueryCumulativePnLType = "cumulative_pnl"
+
+// PauseScheduledOrderSignalName pauses a running ScheduledOrderWorkflow
+// before its next leg is placed.
+const PauseScheduledOrderSignalName = "pause_scheduled_order"
+
+// ResumeScheduledOrderSignalName resumes a paused ScheduledOrderWorkflow.
+const ResumeScheduledOrderSignalName = "resume_scheduled_order"
+
+// ScheduledOrderWorkflowInput is the input for ScheduledOrderWorkflow.
+// BaseOrder supplies the fixed parameters shared by every leg (UserID,
+// EventID, MarketID, SelectionID, Side, Currency); its Odds, Stake and
+// IdempotencyKey are overwritten per leg.
+type ScheduledOrderWorkflowInput struct {
+	BaseOrder *models.PlaceOrderRequest
+	SagaID    string
+
+	NumOrders     int
+	StakePerOrder decimal.Decimal
+
+	// PriceDeviationPct is the fractional odds step applied to each
+	// successive leg (e.g. 0.02 steps the odds 2% further from BaseOrder.Odds
+	// per leg), in the direction that makes the next entry cheaper to
+	// acquire - lower odds for BACK, higher odds for LAY.
+	PriceDeviationPct decimal.Decimal
+
+	// CoolDownInterval is how long the workflow waits between legs.
+	CoolDownInterval time.Duration
+
+	// CircuitBreakLossThreshold is a negative fraction of the total budget
+	// (NumOrders * StakePerOrder); once CumulativePnL/budget falls at or
+	// below it, the workflow halts and stops scheduling further legs.
+	CircuitBreakLossThreshold decimal.Decimal
+
+	// TakeProfitRatio is a positive fraction of the total budget; once
+	// CumulativePnL/budget reaches it, the workflow transitions to
+	// TakingProfit and stops scheduling further legs.
+	TakeProfitRatio decimal.Decimal
+}
+
+// ScheduledOrderLegResult records one leg's outcome within a
+// ScheduledOrderWorkflow run
+type ScheduledOrderLegResult struct {
+	OrderID       string
+	ReservationID string
+	FailureReason string
+}
+
+// ScheduledOrderWorkflowResult is the result of ScheduledOrderWorkflow
+type ScheduledOrderWorkflowResult struct {
+	FinalState    ScheduledOrderState
+	Legs          []*ScheduledOrderLegResult
+	CumulativePnL string
+	StoppedReason string
+}
+
+// ScheduledOrderWorkflow places input.NumOrders child PlaceOrderWorkflow
+// legs at input.CoolDownInterval apart, each leg's odds stepped by
+// input.PriceDeviationPct from the last - a dollar-cost-averaging-style
+// entry strategy. It tracks cumulative P&L across settled legs, realized plus
+// mark-to-market (exposed via the cumulative_pnl query), and halts once that P&L crosses
+// either CircuitBreakLossThreshold or TakeProfitRatio, so a caller doesn't
+// have to babysit the strategy once started. A pause_scheduled_order /
+// resume_scheduled_order signal pair lets an operator hold the strategy
+// between legs without cancelling it outright.
+func ScheduledOrderWorkflow(ctx workflow.Context, input ScheduledOrderWorkflowInput) (*ScheduledOrderWorkflowResult, error) {
+	logger := workflow.GetLogger(ctx)
+	logger.Info("ScheduledOrderWorkflow started", "saga_id", input.SagaID, "num_orders", input.NumOrders)
+
+	result := &ScheduledOrderWorkflowResult{FinalState: ScheduledOrderStateIdle}
+
+	state := ScheduledOrderStateIdle
+	setState := func(s ScheduledOrderState) {
+		state = s
+		if err := workflow.UpsertSearchAttributes(ctx, map[string]interface{}{
+			scheduledOrderStateSearchAttribute: string(s),
+		}); err != nil {
+			logger.Warn("Failed to upsert ScheduledOrderState search attribute", "state", s, "error", err)
+		}
+	}
+
+	cumulativePnL := decimal.Zero
+	if err := workflow.SetQueryHandler(ctx, QueryCumulativePnLType, func() (string, error) {
+		return cumulativePnL.String(), nil
+	}); err != nil {
+		return result, fmt.Errorf("register cumulative_pnl query handler: %w", err)
+	}
+
+	paused := false
+	pauseCh := workflow.GetSignalChannel(ctx, PauseScheduledOrderSignalName)
+	resumeCh := workflow.GetSignalChannel(ctx, ResumeScheduledOrderSignalName)
+	workflow.Go(ctx, func(gCtx workflow.Context) {
+		for {
+			selector := workflow.NewSelector(gCtx)
+			selector.AddReceive(pauseCh, func(c workflow.ReceiveChannel, more bool) {
+				c.Receive(gCtx, nil)
+				paused = true
+				logger.Info("ScheduledOrderWorkflow paused")
+			})
+			selector.AddReceive(resumeCh, func(c workflow.ReceiveChannel, more bool) {
+				c.Receive(gCtx, nil)
+				paused = false
+				logger.Info("ScheduledOrderWorkflow resumed")
+			})
+			selector.Select(gCtx)
+		}
+	})
+
+	// Fetch the order-book's own view of outstanding risk on this selection
+	// before scheduling anything. The value is only logged: legs are still
+	// scheduled from leg 0, whatever a prior attempt already placed.
+	recoverCtx := workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		StartToCloseTimeout: 10 * time.Second,
+		RetryPolicy:         &workflow.RetryPolicy{MaximumAttempts: 3},
+	})
+	var recoverResult *RecoverOrdersResult
+	if err := workflow.ExecuteActivity(recoverCtx, RecoverOrdersActivity, RecoverOrdersInput{
+		UserID:      input.BaseOrder.UserID.String(),
+		MarketID:    input.BaseOrder.MarketID,
+		SelectionID: input.BaseOrder.SelectionID,
+	}).Get(recoverCtx, &recoverResult); err != nil {
+		logger.Warn("RecoverOrders failed, proceeding without reconciliation", "error", err)
+	} else {
+		logger.Info("Reconciled outstanding risk on restart", "open_risk", recoverResult.OpenRisk)
+	}
+
+	setState(ScheduledOrderStateOpening)
+
+	activityOptions := workflow.ActivityOptions{
+		StartToCloseTimeout: 10 * time.Second,
+		RetryPolicy: &workflow.RetryPolicy{
+			InitialInterval:    time.Second,
+			BackoffCoefficient: 2.0,
+			MaximumInterval:    time.Minute,
+			MaximumAttempts:    3,
+		},
+	}
+	ctx = workflow.WithActivityOptions(ctx, activityOptions)
+
+	totalBudget := input.StakePerOrder.Mul(decimal.NewFromInt(int64(input.NumOrders)))
+
+	var settledOrderIDs []string
+
+	// openLegs tracks the entry odds of every settled leg so the circuit
+	// breaker can mark them to the market's current price. Fill.PnL is zero
+	// until a settlement process resolves the event's outcome (see
+	// RecordFill), so realized PnL from GetCumulativePnLActivity never moves
+	// while a strategy is still running - a breaker gated on it alone would
+	// never actually trip. Unrealized, mark-to-market PnL is what moves
+	// between legs and is what CircuitBreakLossThreshold/TakeProfitRatio are
+	// meant to watch.
+	var openLegs []markToMarketLeg
+
+	// lastRealizedPnL/lastUnrealizedPnL hold the most recently successful
+	// reading of each component, so a single failed lookup falls back to
+	// that component's own last value instead of resetting the whole
+	// cumulative total to zero.
+	lastRealizedPnL := decimal.Zero
+	lastUnrealizedPnL := decimal.Zero
+
+	setState(ScheduledOrderStateRunning)
+
+	for i := 0; i < input.NumOrders; i++ {
+		if err := workflow.Await(ctx, func() bool { return !paused }); err != nil {
+			return result, fmt.Errorf("await resume: %w", err)
+		}
+
+		if len(settledOrderIDs) > 0 {
+			var pnlResult *GetCumulativePnLResult
+			if err := workflow.ExecuteActivity(ctx, GetCumulativePnLActivity, GetCumulativePnLInput{
+				OrderIDs: settledOrderIDs,
+			}).Get(ctx, &pnlResult); err != nil {
+				logger.Warn("Failed to refresh realized PnL, using last known value", "error", err)
+			} else if parsed, parseErr := decimal.NewFromString(pnlResult.CumulativePnL); parseErr == nil {
+				lastRealizedPnL = parsed
+			}
+
+			var marketStateResult *GetMarketStateResult
+			if err := workflow.ExecuteActivity(ctx, GetMarketStateActivity, GetMarketStateInput{
+				MarketID: input.BaseOrder.MarketID,
+			}).Get(ctx, &marketStateResult); err != nil {
+				logger.Warn("Failed to refresh market state for mark-to-market PnL, using last known value", "error", err)
+			} else {
+				currentOdds := marketStateResult.State.MarkPrice
+				unrealizedPnL := decimal.Zero
+				for _, leg := range openLegs {
+					unrealizedPnL = unrealizedPnL.Add(markToMarketPnL(input.BaseOrder.Side, leg.entryOdds, currentOdds, leg.stake))
+				}
+				lastUnrealizedPnL = unrealizedPnL
+			}
+
+			cumulativePnL = lastRealizedPnL.Add(lastUnrealizedPnL)
+
+			if totalBudget.IsPositive() {
+				pnlRatio := cumulativePnL.Div(totalBudget)
+
+				if !input.CircuitBreakLossThreshold.IsZero() && pnlRatio.LessThanOrEqual(input.CircuitBreakLossThreshold) {
+					logger.Warn("Circuit breaker tripped, halting remaining legs", "pnl_ratio", pnlRatio, "threshold", input.CircuitBreakLossThreshold)
+					setState(ScheduledOrderStateCircuitBroken)
+					result.StoppedReason = fmt.Sprintf("circuit breaker tripped: pnl ratio %s crossed threshold %s", pnlRatio, input.CircuitBreakLossThreshold)
+					break
+				}
+
+				if !input.TakeProfitRatio.IsZero() && pnlRatio.GreaterThanOrEqual(input.TakeProfitRatio) {
+					logger.Info("Take-profit target reached, halting remaining legs", "pnl_ratio", pnlRatio, "target", input.TakeProfitRatio)
+					setState(ScheduledOrderStateTakingProfit)
+					result.StoppedReason = fmt.Sprintf("take-profit target reached: pnl ratio %s crossed target %s", pnlRatio, input.TakeProfitRatio)
+					break
+				}
+			}
+		}
+
+		leg := &ScheduledOrderLegResult{}
+
+		legSagaID := fmt.Sprintf("%s-leg-%d", input.SagaID, i)
+		legOdds := deviatedOdds(input.BaseOrder.Odds, input.PriceDeviationPct, i, input.BaseOrder.Side)
+		childInput := PlaceOrderWorkflowInput{
+			OrderRequest: &models.PlaceOrderRequest{
+				UserID:         input.BaseOrder.UserID,
+				EventID:        input.BaseOrder.EventID,
+				MarketID:       input.BaseOrder.MarketID,
+				SelectionID:    input.BaseOrder.SelectionID,
+				Side:           input.BaseOrder.Side,
+				Odds:           legOdds,
+				Stake:          input.StakePerOrder,
+				Currency:       input.BaseOrder.Currency,
+				IdempotencyKey: legSagaID,
+				Metadata:       input.BaseOrder.Metadata,
+			},
+			SagaID: legSagaID,
+		}
+
+		childCtx := workflow.WithChildOptions(ctx, workflow.ChildWorkflowOptions{WorkflowID: legSagaID})
+
+		var childResult PlaceOrderWorkflowResult
+		err := workflow.ExecuteChildWorkflow(childCtx, PlaceOrderWorkflow, childInput).Get(childCtx, &childResult)
+		if err != nil || childResult.Status != models.OrderStatusSettled {
+			leg.FailureReason = childResult.FailureReason
+			if leg.FailureReason == "" && err != nil {
+				leg.FailureReason = err.Error()
+			}
+			logger.Warn("Scheduled order leg failed", "leg", i, "saga_id", legSagaID, "failure_reason", leg.FailureReason)
+			result.Legs = append(result.Legs, leg)
+			if i < input.NumOrders-1 {
+				if err := workflow.Sleep(ctx, input.CoolDownInterval); err != nil {
+					return result, fmt.Errorf("cooldown sleep: %w", err)
+				}
+			}
+			continue
+		}
+
+		leg.OrderID = childResult.OrderID
+		leg.ReservationID = childResult.ReservationID
+		result.Legs = append(result.Legs, leg)
+		settledOrderIDs = append(settledOrderIDs, leg.OrderID)
+		openLegs = append(openLegs, markToMarketLeg{entryOdds: legOdds, stake: input.StakePerOrder})
+
+		logger.Info("Scheduled order leg settled", "leg", i, "order_id", leg.OrderID)
+
+		if i < input.NumOrders-1 {
+			if err := workflow.Sleep(ctx, input.CoolDownInterval); err != nil {
+				return result, fmt.Errorf("cooldown sleep: %w", err)
+			}
+		}
+	}
+
+	// The terminal search-attribute state is always STOPPED; CircuitBroken/
+	// TakingProfit (if either fired) were already recorded transiently above
+	// and are captured for good in result.StoppedReason.
+	setState(ScheduledOrderStateStopped)
+
+	result.FinalState = state
+	result.CumulativePnL = cumulativePnL.String()
+
+	logger.Info("ScheduledOrderWorkflow completed", "saga_id", input.SagaID, "legs", len(result.Legs), "cumulative_pnl", result.CumulativePnL)
+
+	return result, nil
+}
+
+// markToMarketLeg is one settled leg's entry price and stake, enough to mark
+// it to the market's current price without re-fetching each leg's own fills
+type markToMarketLeg struct {
+	entryOdds decimal.Decimal
+	stake     decimal.Decimal
+}
+
+// markToMarketPnL estimates the unrealized profit or loss of a single-leg
+// position if it were closed out (traded out) at currentOdds right now,
+// using the standard betting-exchange "green up" derivation: the stake
+// needed on the opposite side at currentOdds to lock in a guaranteed
+// outcome. For a BACK bet that's stake*(entryOdds-currentOdds)/currentOdds;
+// a LAY bet is the mirror image. Odds shortening (currentOdds < entryOdds)
+// is a profit for BACK and a loss for LAY, matching how the position would
+// actually trade.
+func markToMarketPnL(side models.OrderSide, entryOdds, currentOdds, stake decimal.Decimal) decimal.Decimal {
+	if currentOdds.IsZero() {
+		return decimal.Zero
+	}
+
+	if side == models.OrderSideBack {
+		return stake.Mul(entryOdds.Sub(currentOdds)).Div(currentOdds)
+	}
+	return stake.Mul(currentOdds.Sub(entryOdds)).Div(currentOdds)
+}
+
+// deviatedOdds steps baseOdds by n increments of pct, in the direction that
+// makes each successive leg a cheaper entry: lower odds for a BACK order,
+// higher odds for a LAY order. n == 0 returns baseOdds unchanged.
+func deviatedOdds(baseOdds, pct decimal.Decimal, n int, side models.OrderSide) decimal.Decimal {
+	if n == 0 || pct.IsZero() {
+		return baseOdds
+	}
+
+	step := baseOdds.Mul(pct).Mul(decimal.NewFromInt(int64(n)))
+	if side == models.OrderSideBack {
+		return baseOdds.Sub(step)
+	}
+	return baseOdds.Add(step)
+}